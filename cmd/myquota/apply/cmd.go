@@ -0,0 +1,90 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github/yasun1/myquota/cmd/myquota/plan"
+	"github/yasun1/myquota/pkg/quota"
+
+	"github.com/spf13/cobra"
+)
+
+var args struct {
+	file    string
+	overlay string
+	vars    []string
+	prune   bool
+	force   bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply the quota manifest",
+	Long: "Make the changes shown by 'myquota plan', so that the resource quotas of the organizations match " +
+		"the quota manifest with the overlay of the active environment. The changes are applied one by one, " +
+		"and the progress is reported when the command is interrupted.",
+	Args: cobra.NoArgs,
+	Run:  run,
+}
+
+func init() {
+	plan.AddFlags(Cmd, &args.file, &args.overlay, &args.vars, &args.prune)
+	fs := Cmd.Flags()
+	fs.BoolVar(
+		&args.force,
+		"force",
+		false,
+		"If the force is true, will also delete the pruned resource quotas that are in use.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	ctx := cmd.Context()
+	m, err := plan.Load(args.file, args.overlay, args.vars)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	changes, err := quota.Plan(ctx, m, args.prune)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if err = quota.FPrintChanges(changes); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	applied, err := quota.ApplyChanges(ctx, changes, args.force)
+	// The usage of the unknown skus can't be printed, they only count as in use:
+	if errors.Is(err, quota.ErrInUse) && changes[applied].Sku.QuotaID != "" {
+		change := changes[applied]
+		if printErr := quota.FPrintUsageForSkus(ctx, change.OrgID, change.Sku); printErr != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", printErr)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fmt.Fprintf(os.Stderr, "[E] Stopped after applying %d of %d changes.\n", applied, len(changes))
+		os.Exit(1)
+	}
+}
@@ -17,10 +17,18 @@ limitations under the License.
 package assign
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
+	"os/user"
+	"time"
 
+	"github/yasun1/myquota/pkg/connection"
+	"github/yasun1/myquota/pkg/group"
+	"github/yasun1/myquota/pkg/lease"
 	"github/yasun1/myquota/pkg/quota"
+	"github/yasun1/myquota/pkg/term"
 
 	"github.com/spf13/cobra"
 )
@@ -30,14 +38,21 @@ var args struct {
 	qtype    string
 	number   int
 	force    bool
+	ttl      time.Duration
+	reason   string
 }
 
 var Cmd = &cobra.Command{
-	Use:   "assign <skuID>",
+	Use:   "assign <skuID>...",
 	Short: "Assign the resource quota to the account",
 	Long: "Assign the  resource quota to the account. " +
 		"If the resource quota does not exist, create the resource quota for it; " +
-		"If the resource quota exists, update the resource quota to the specified value.",
+		"If the resource quota exists, update the resource quota to the specified value. " +
+		"If several skuIDs are specified, they are assigned one by one and the progress is reported " +
+		"when the command is interrupted. " +
+		"With the option '--ttl', the previous value is recorded in a lease, and 'myquota gc' reverts it " +
+		"when the lease expires. Without it, the leases of the resource quota are released. " +
+		"With the option '--group', will assign the resource quota to every organization of the group.",
 	Run: run,
 }
 
@@ -64,18 +79,41 @@ func init() {
 		0,
 		"The number is the applied sku account.",
 	)
+	fs.DurationVar(
+		&args.ttl,
+		"ttl",
+		0,
+		"The time after which the assignment expires and is reverted by 'myquota gc', etc, 48h. "+
+			"Zero means the assignment is permanent.",
+	)
+	fs.StringVar(
+		&args.reason,
+		"reason",
+		"",
+		"The reason of the temporary assignment, etc, a ticket. It is recorded in the lease.",
+	)
+	group.AddFlags(fs)
 }
 
 func run(cmd *cobra.Command, argv []string) {
-	if args.username == "" {
+	switch {
+	case group.Name() != "" && args.username != "":
+		fmt.Fprintf(os.Stderr, "[E] The options '--username' and '--group' can't be used together.\n\n")
+		os.Exit(1)
+	case group.Name() == "" && args.username == "":
 		fmt.Fprintf(os.Stderr, "[E] The option '--username' is mandatory.\n\n")
 		os.Exit(1)
 	}
 
-	orgID, err := quota.GetOrgID(args.username)
-	if err != nil {
-		fmt.Printf("%v\n", err)
-		os.Exit(0)
+	ctx := cmd.Context()
+	var orgID string
+	var err error
+	if group.Name() == "" {
+		orgID, err = quota.GetOrgID(ctx, args.username)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
 	}
 
 	if len(argv) == 0 {
@@ -83,21 +121,191 @@ func run(cmd *cobra.Command, argv []string) {
 		os.Exit(1)
 	}
 
-	skuName := argv[0]
-	skuMap := quota.AllSkus()
-	if _, existed := skuMap[skuName]; !existed {
-		panic(fmt.Errorf("[E] The input sku '%s' is invalid", skuName))
+	skuMap, err := quota.AllSkus(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	var skus []quota.Sku
+	for _, skuName := range argv {
+		if _, existed := skuMap[skuName]; !existed {
+			fmt.Fprintf(os.Stderr, "[E] The input sku '%s' is invalid.\n", skuName)
+			os.Exit(1)
+		}
+		sku := skuMap[skuName]
+		sku.Allowed = args.number
+		sku.Type = args.qtype
+		skus = append(skus, sku)
+	}
+
+	if args.ttl < 0 {
+		fmt.Fprintf(os.Stderr, "[E] The ttl must not be negative.\n\n")
+		os.Exit(1)
+	}
+	if args.reason != "" && args.ttl == 0 {
+		fmt.Fprintf(os.Stderr, "[E] The option '--reason' requires the option '--ttl'.\n\n")
+		os.Exit(1)
+	}
+	if group.Name() != "" {
+		runGroup(ctx, skus)
+		return
+	}
+
+	// Save the previous values for the leases before changing them
+	var snapshot *quota.Snapshot
+	if args.ttl > 0 {
+		snapshot, err = quota.TakeSnapshot(ctx, orgID, skus...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
 	}
-	sku := skuMap[skuName]
-	sku.Allowed = args.number
-	sku.Type = args.qtype
 
 	// Assign quota
-	_, err = quota.AssignQuota(orgID, sku)
+	applied, err := quota.AssignQuotas(ctx, orgID, skus...)
+	if snapshot != nil {
+		if leaseErr := recordLeases(ctx, args.username, orgID, snapshot, skus[:applied]); leaseErr != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", leaseErr)
+			fmt.Fprintf(os.Stderr, "[E] Failed to record the leases, the assigned resource quota won't be reverted.\n")
+			os.Exit(1)
+		}
+	}
+	if snapshot == nil {
+		if leaseErr := releaseLeases(ctx, orgID, skus[:applied]); leaseErr != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", leaseErr)
+			fmt.Fprintf(os.Stderr, "[E] Failed to release the leases, 'myquota gc' may revert the assigned resource quota.\n")
+			os.Exit(1)
+		}
+	}
 	if err != nil {
-		panic(err)
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fmt.Fprintf(os.Stderr, "[E] Stopped after assigning %d of %d resource quotas.\n", applied, len(skus))
+		os.Exit(1)
 	}
 
 	// Print the usage of the just assigned quota
-	quota.FPrintUsageForSkus(orgID, sku)
+	err = quota.FPrintUsageForSkus(ctx, orgID, skus...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+// recordLeases records a lease for each applied sku, with its value in the snapshot.
+func recordLeases(ctx context.Context, username string, orgID string, snapshot *quota.Snapshot, applied []quota.Sku) error {
+	environment, err := connection.Environment()
+	if err != nil {
+		return err
+	}
+	unlock, err := lease.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	store, err := lease.Load()
+	if err != nil {
+		return err
+	}
+	owner := os.Getenv("USER")
+	if current, err := user.Current(); err == nil {
+		owner = current.Username
+	}
+	now := time.Now().UTC()
+	for i, sku := range applied {
+		saved := snapshot.Quotas[i]
+		l := store.Put(&lease.Lease{
+			Environment: environment,
+			Username:    username,
+			OrgID:       orgID,
+			Sku:         sku.Name,
+			QuotaID:     sku.QuotaID,
+			Type:        sku.Type,
+			Existed:     saved.Existed,
+			Previous:    saved.Sku.Allowed,
+			Count:       sku.Allowed,
+			Owner:       owner,
+			Reason:      args.reason,
+			Created:     now,
+			Expires:     now.Add(args.ttl),
+		})
+		slog.InfoContext(ctx, "Leased the resource quota", "lease", l.ID, "sku", sku.Name, "type", sku.Type,
+			"previous", l.Previous, "sku_count", l.Count, "expires", l.Expires, "org_id", orgID)
+	}
+	return store.Save()
+}
+
+// releaseLeases deletes the leases of the applied skus, as assigning them without a ttl makes them
+// permanent, so that 'myquota gc' doesn't revert them.
+func releaseLeases(ctx context.Context, orgID string, applied []quota.Sku) error {
+	environment, err := connection.Environment()
+	if err != nil {
+		return err
+	}
+	unlock, err := lease.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	store, err := lease.Load()
+	if err != nil {
+		return err
+	}
+	var released int
+	for _, sku := range applied {
+		l := store.Find(environment, orgID, sku.Name, sku.Type)
+		if l == nil {
+			continue
+		}
+		store.Delete(l.ID)
+		released++
+		slog.InfoContext(ctx, "Released the lease of the resource quota", "lease", l.ID, "sku", sku.Name,
+			"type", sku.Type, "org_id", orgID)
+	}
+	if released == 0 {
+		return nil
+	}
+	return store.Save()
+}
+
+// runGroup assigns the resource quota to every organization of the group, and prints the usage
+// of the assigned quota of all of them.
+func runGroup(ctx context.Context, skus []quota.Sku) {
+	members, err := group.Members()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	results := group.Run(ctx, members, func(ctx context.Context, member group.Member) ([]quota.Usage, error) {
+		var snapshot *quota.Snapshot
+		var err error
+		if args.ttl > 0 {
+			snapshot, err = quota.TakeSnapshot(ctx, member.OrgID, skus...)
+			if err != nil {
+				return nil, err
+			}
+		}
+		applied, err := quota.AssignQuotas(ctx, member.OrgID, skus...)
+		if snapshot != nil {
+			if leaseErr := recordLeases(ctx, member.Username, member.OrgID, snapshot, skus[:applied]); leaseErr != nil {
+				return nil, fmt.Errorf("[E] Failed to record the leases, the assigned resource quota won't be reverted: %w", leaseErr)
+			}
+		}
+		if snapshot == nil {
+			if leaseErr := releaseLeases(ctx, member.OrgID, skus[:applied]); leaseErr != nil {
+				return nil, fmt.Errorf("[E] Failed to release the leases, 'myquota gc' may revert the assigned resource quota: %w", leaseErr)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("[E] Stopped after assigning %d of %d resource quotas: %w", applied, len(skus), err)
+		}
+		return quota.SkuUsage(ctx, member.OrgID, skus...)
+	})
+	if err = group.FPrintUsage(results, term.UseColor(os.Stdout)); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if group.FPrintErrors(results) != 0 {
+		os.Exit(1)
+	}
 }
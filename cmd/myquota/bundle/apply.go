@@ -0,0 +1,36 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+
+	"github/yasun1/myquota/pkg/quota"
+
+	"github.com/spf13/cobra"
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply <bundle>",
+	Short: "Assign the resource quota of the bundle to the account",
+	Long: "Assign each sku of the bundle with its count to the organization that the account is belonged to, " +
+		"creating or updating the resource quota.",
+	Args: cobra.ExactArgs(1),
+	Run:  runApply,
+}
+
+func runApply(cmd *cobra.Command, argv []string) {
+	orgID, skus := resolve(cmd, argv[0])
+
+	ctx := cmd.Context()
+	applied, err := quota.AssignQuotas(ctx, orgID, skus...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fmt.Fprintf(os.Stderr, "[E] Stopped after assigning %d of %d resource quotas.\n", applied, len(skus))
+		os.Exit(1)
+	}
+
+	if _, err = quota.FPrintBundleStatus(ctx, orgID, skus...); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"fmt"
+	"os"
+
+	"github/yasun1/myquota/pkg/config"
+	"github/yasun1/myquota/pkg/quota"
+
+	"github.com/spf13/cobra"
+)
+
+var args struct {
+	username   string
+	qtype      string
+	bundleFile string
+	force      bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Manage the bundles of resource quota under the account",
+	Long: "Manage the bundles, the named groups of skus with their count that are assigned together. " +
+		"The bundles are defined in the configuration file, or in the bundle file. " +
+		"Every sku of the bundle is checked before any change.",
+}
+
+func init() {
+	fs := Cmd.PersistentFlags()
+	fs.StringVarP(
+		&args.username,
+		"username",
+		"u",
+		"",
+		"The username of the account.",
+	)
+	fs.StringVarP(
+		&args.qtype,
+		"qtype",
+		"t",
+		"Manual",
+		"The type of the quota.",
+	)
+	fs.StringVar(
+		&args.bundleFile,
+		"bundle-file",
+		os.Getenv("MYQUOTA_BUNDLES"),
+		"The file that contains more bundles, with the same format as the configuration file. "+
+			"The default is the value of 'MYQUOTA_BUNDLES'.",
+	)
+
+	Cmd.AddCommand(applyCmd)
+	Cmd.AddCommand(removeCmd)
+	Cmd.AddCommand(statusCmd)
+}
+
+// resolve returns the organization of the account and the skus of the bundle, or exits.
+func resolve(cmd *cobra.Command, name string) (string, []quota.Sku) {
+	if args.username == "" {
+		fmt.Fprintf(os.Stderr, "[E] The option '--username' is mandatory.\n\n")
+		os.Exit(1)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	bundle, err := cfg.Bundle(name, args.bundleFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	ctx := cmd.Context()
+	skus, err := quota.BundleSkus(ctx, bundle, args.qtype)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	orgID, err := quota.GetOrgID(ctx, args.username)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	return orgID, skus
+}
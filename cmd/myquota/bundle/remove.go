@@ -0,0 +1,47 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github/yasun1/myquota/pkg/quota"
+
+	"github.com/spf13/cobra"
+)
+
+var removeCmd = &cobra.Command{
+	Use:   "remove <bundle>",
+	Short: "Remove the resource quota of the bundle from the account",
+	Long: "Remove the resource quota of each sku of the bundle from the organization that the account is belonged to. " +
+		"The resource quota that is in use is only removed with the option '--force'.",
+	Args: cobra.ExactArgs(1),
+	Run:  runRemove,
+}
+
+func init() {
+	removeCmd.Flags().BoolVarP(
+		&args.force,
+		"force",
+		"f",
+		false,
+		"If the force is true, will ignore checking the consumed quota and forcely remove the quota from the organization.",
+	)
+}
+
+func runRemove(cmd *cobra.Command, argv []string) {
+	orgID, skus := resolve(cmd, argv[0])
+
+	ctx := cmd.Context()
+	removed, err := quota.RemoveQuotas(ctx, orgID, args.force, skus...)
+	if errors.Is(err, quota.ErrInUse) {
+		if printErr := quota.FPrintUsageForSkus(ctx, orgID, skus[removed]); printErr != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", printErr)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fmt.Fprintf(os.Stderr, "[E] Stopped after removing %d of %d resource quotas.\n", removed, len(skus))
+		os.Exit(1)
+	}
+}
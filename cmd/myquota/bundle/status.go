@@ -0,0 +1,32 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+
+	"github/yasun1/myquota/pkg/quota"
+
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status <bundle>",
+	Short: "Show whether the bundle is applied to the account",
+	Long: "Show whether each resource quota of the bundle is assigned with the count of the bundle, " +
+		"and exit with 1 if any of them isn't.",
+	Args: cobra.ExactArgs(1),
+	Run:  runStatus,
+}
+
+func runStatus(cmd *cobra.Command, argv []string) {
+	orgID, skus := resolve(cmd, argv[0])
+
+	applied, err := quota.FPrintBundleStatus(cmd.Context(), orgID, skus...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if !applied {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package check
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github/yasun1/myquota/pkg/group"
+	"github/yasun1/myquota/pkg/quota"
+
+	"github.com/spf13/cobra"
+)
+
+var args struct {
+	username string
+	warn     float64
+	crit     float64
+}
+
+var Cmd = &cobra.Command{
+	Use:   "check <skuIDs>",
+	Short: "Check the utilization of the quota against thresholds",
+	Long: "Check the utilization (Consumed/Allowed) of the quota in the organization that the account is belonged to, " +
+		"and exit with the Nagios plugin codes: 0 for OK, 1 for warning, 2 for critical and 3 for unknown. " +
+		"If no skuIDs are specified, will check all the allowed or consumed quota of the organization. " +
+		"With the option '--group', will check every organization of the group, and report the worst status " +
+		"followed by the result of each organization.",
+	Run: run,
+}
+
+func init() {
+	fs := Cmd.Flags()
+	fs.StringVarP(
+		&args.username,
+		"username",
+		"u",
+		"",
+		"The username of the account.",
+	)
+	fs.Float64Var(
+		&args.warn,
+		"warn",
+		80,
+		"The utilization percentage from which the quota is in warning.",
+	)
+	fs.Float64Var(
+		&args.crit,
+		"crit",
+		95,
+		"The utilization percentage from which the quota is critical.",
+	)
+	group.AddFlags(fs)
+}
+
+// unknown prints the reason why the check couldn't be done, and exits with the unknown code.
+func unknown(format string, a ...interface{}) {
+	fmt.Printf("QUOTA %s - %s\n", quota.StatusUnknown, fmt.Sprintf(format, a...))
+	os.Exit(int(quota.StatusUnknown))
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	switch {
+	case group.Name() != "" && args.username != "":
+		unknown("The options '--username' and '--group' can't be used together.")
+	case group.Name() == "" && args.username == "":
+		unknown("The option '--username' is mandatory.")
+	}
+	if args.warn < 0 || args.crit < 0 || args.warn > args.crit {
+		unknown("The thresholds are invalid, expect 0 <= warn (%g) <= crit (%g).", args.warn, args.crit)
+	}
+
+	ctx := cmd.Context()
+	var quotaIDs []string
+	if len(argv) != 0 {
+		skuMap, err := quota.AllSkus(ctx)
+		if err != nil {
+			unknown("%v", err)
+		}
+		for _, skuName := range argv {
+			sku, existed := skuMap[skuName]
+			if !existed {
+				unknown("The sku '%s' is invalid.", skuName)
+			}
+			quotaIDs = append(quotaIDs, sku.QuotaID)
+		}
+	}
+
+	if group.Name() != "" {
+		runGroup(ctx, quotaIDs)
+	}
+
+	orgID, err := quota.GetOrgID(ctx, args.username)
+	if err != nil {
+		unknown("%v", err)
+	}
+	usages, err := quota.OrgUsage(ctx, orgID)
+	if err != nil {
+		unknown("%v", err)
+	}
+
+	result := quota.CheckUsage(orgID, usages, args.warn, args.crit, quotaIDs...)
+	fmt.Println(result.Summary())
+	os.Exit(int(result.Status))
+}
+
+// runGroup checks every organization of the group, and exits with the worst status. The
+// organizations that can't be checked are unknown.
+func runGroup(ctx context.Context, quotaIDs []string) {
+	members, err := group.Members()
+	if err != nil {
+		unknown("%v", err)
+	}
+	results := group.Run(ctx, members, func(ctx context.Context, member group.Member) (*quota.CheckResult, error) {
+		usages, err := quota.OrgUsage(ctx, member.OrgID)
+		if err != nil {
+			return nil, err
+		}
+		return quota.CheckUsage(member.OrgID, usages, args.warn, args.crit, quotaIDs...), nil
+	})
+
+	checks := make([]quota.GroupCheck, len(results))
+	for i, result := range results {
+		checks[i] = quota.GroupCheck{Label: result.Label(), Result: result.Value, Err: result.Err}
+	}
+	status, summary := quota.GroupSummary(group.Name(), checks)
+	fmt.Println(summary)
+	os.Exit(int(status))
+}
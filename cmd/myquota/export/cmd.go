@@ -0,0 +1,155 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github/yasun1/myquota/pkg/connection"
+	"github/yasun1/myquota/pkg/manifest"
+	"github/yasun1/myquota/pkg/quota"
+
+	"github.com/spf13/cobra"
+)
+
+var args struct {
+	usernames []string
+	qtype     string
+	output    string
+	file      string
+	stripZero bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the resource quota under the accounts to a manifest",
+	Long: "Export the resource quota assigned to the organizations that the accounts are belonged to, " +
+		"as a manifest of the sku, the type and the count of each resource quota per organization.",
+	Args: cobra.NoArgs,
+	Run:  run,
+}
+
+func init() {
+	fs := Cmd.Flags()
+	fs.StringSliceVarP(
+		&args.usernames,
+		"username",
+		"u",
+		nil,
+		"The usernames of the accounts, separated by commas or repeated.",
+	)
+	fs.StringVarP(
+		&args.qtype,
+		"type",
+		"t",
+		"",
+		"If the type is set, will only export the resource quota of the type, etc, 'Manual'.",
+	)
+	fs.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		manifest.FormatYAML,
+		"The format of the manifest, 'yaml' or 'json'.",
+	)
+	fs.StringVarP(
+		&args.file,
+		"file",
+		"f",
+		"",
+		"The file the manifest is written to. By default the manifest is written to the standard output.",
+	)
+	fs.BoolVar(
+		&args.stripZero,
+		"strip-zero",
+		false,
+		"If the strip-zero is true, will not export the resource quota whose count is zero.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	if len(args.usernames) == 0 {
+		fmt.Fprintf(os.Stderr, "[E] The option '--username' is mandatory.\n\n")
+		os.Exit(1)
+	}
+	if args.output != manifest.FormatYAML && args.output != manifest.FormatJSON {
+		fmt.Fprintf(os.Stderr, "[E] The format '%s' is invalid, valid formats are 'yaml' and 'json'.\n", args.output)
+		os.Exit(1)
+	}
+
+	ctx := cmd.Context()
+	// The gateways of no known environment are named after the profile, which the manifests don't accept:
+	environment, err := connection.ProfileEnvironment()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	m := &manifest.Manifest{}
+	if manifest.IsEnvironment(environment) {
+		m.Environment = environment
+	}
+	for _, username := range args.usernames {
+		orgID, err := quota.GetOrgID(ctx, username)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		resourceQuotas, err := quota.OrgResourceQuotas(ctx, orgID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+
+		org := &manifest.Org{
+			Username: username,
+			OrgID:    orgID,
+			Quotas:   []*manifest.Quota{},
+		}
+		for _, resourceQuota := range resourceQuotas {
+			if args.qtype != "" && resourceQuota.Type != args.qtype {
+				continue
+			}
+			org.Quotas = append(org.Quotas, &manifest.Quota{
+				Sku:      resourceQuota.Sku,
+				Type:     resourceQuota.Type,
+				SkuCount: resourceQuota.SkuCount,
+			})
+		}
+		m.Orgs = append(m.Orgs, org)
+	}
+	m.Sort()
+	if args.stripZero {
+		m.StripZero()
+	}
+
+	var writer io.Writer = os.Stdout
+	if args.file != "" {
+		file, err := os.Create(args.file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[E] Failed to create the manifest file: %v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		writer = file
+	}
+	if err := m.Write(writer, args.output); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github/yasun1/myquota/pkg/exporter"
+
+	"github.com/spf13/cobra"
+)
+
+var args struct {
+	listen    string
+	usernames []string
+	interval  time.Duration
+}
+
+var Cmd = &cobra.Command{
+	Use:   "exporter",
+	Short: "Expose the quota of the organizations as Prometheus metrics",
+	Long: "Poll periodically the quota cost of the organizations that the accounts are belonged to, " +
+		"and expose the allowed and consumed quota as Prometheus metrics on '/metrics'.",
+	Run: run,
+}
+
+func init() {
+	fs := Cmd.Flags()
+	fs.StringVar(
+		&args.listen,
+		"listen",
+		":9099",
+		"The address the metrics are served on.",
+	)
+	fs.StringSliceVarP(
+		&args.usernames,
+		"username",
+		"u",
+		nil,
+		"The usernames of the accounts, separated by commas or repeated.",
+	)
+	fs.DurationVar(
+		&args.interval,
+		"interval",
+		5*time.Minute,
+		"The time between the polls of the quota cost.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	if len(args.usernames) == 0 {
+		fmt.Fprintf(os.Stderr, "[E] The option '--username' is mandatory.\n")
+		os.Exit(1)
+	}
+	if args.interval <= 0 {
+		fmt.Fprintf(os.Stderr, "[E] The interval must be positive.\n")
+		os.Exit(1)
+	}
+
+	ctx := cmd.Context()
+	e := exporter.New(args.usernames, args.interval)
+	go e.Run(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", e.Handler())
+	server := &http.Server{
+		Addr:              args.listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	slog.InfoContext(ctx, "Serving the metrics", "listen", args.listen, "users", args.usernames,
+		"interval", args.interval)
+	err := server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "[E] Failed to serve the metrics: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forecast
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github/yasun1/myquota/pkg/connection"
+	"github/yasun1/myquota/pkg/history"
+
+	"github.com/spf13/cobra"
+)
+
+var args struct {
+	username string
+	window   time.Duration
+	days     int
+}
+
+var Cmd = &cobra.Command{
+	Use:   "forecast <skuIDs>",
+	Short: "Forecast when the quota under the account runs out",
+	Long: "Fit the trend of the consumption of each quota recorded by 'myquota record' for the account, " +
+		"and estimate when the consumption reaches the allowed quota at the current growth rate. " +
+		"The quotas that run out within the number of days set by '--days' are flagged as 'AT RISK', " +
+		"and the command exits with 1 if any quota is at risk or exhausted. " +
+		"The skuIDs may also be quota ids, etc, 'cluster|byoc|osd'. " +
+		"If no skuIDs are specified, will forecast all the recorded quota of the account.",
+	Run: run,
+}
+
+func init() {
+	fs := Cmd.Flags()
+	fs.StringVarP(
+		&args.username,
+		"username",
+		"u",
+		"",
+		"The username of the account.",
+	)
+	fs.DurationVar(
+		&args.window,
+		"window",
+		7*24*time.Hour,
+		"The window of the history the trend is fitted on, up to now.",
+	)
+	fs.IntVar(
+		&args.days,
+		"days",
+		7,
+		"The number of days from now within which a quota that runs out is flagged.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	if args.username == "" {
+		fmt.Fprintf(os.Stderr, "[E] The option '--username' is mandatory.\n\n")
+		os.Exit(1)
+	}
+
+	now := time.Now()
+	environment, err := connection.Environment()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	samples, err := history.Read(environment, args.username, now.Add(-args.window), argv...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if len(samples) == 0 {
+		fmt.Fprintf(os.Stderr, "[E] No usage was recorded for the account '%s' in the last %s.\n",
+			args.username, args.window)
+		os.Exit(1)
+	}
+
+	within := time.Duration(args.days) * 24 * time.Hour
+	forecasts := history.ForecastAll(samples)
+	if err = history.FPrintForecasts(forecasts, now, within); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	for _, forecast := range forecasts {
+		if forecast.Exhausts(now, within) {
+			os.Exit(1)
+		}
+	}
+}
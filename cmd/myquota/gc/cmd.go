@@ -0,0 +1,133 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gc
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github/yasun1/myquota/pkg/connection"
+	"github/yasun1/myquota/pkg/lease"
+	"github/yasun1/myquota/pkg/quota"
+
+	"github.com/spf13/cobra"
+)
+
+var args struct {
+	dryRun bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Revert the expired leases of the resource quota",
+	Long: "Revert the resource quota of the expired leases of the environment to the value before the lease, " +
+		"or remove it if it didn't exist. The leases whose quota is still in use, " +
+		"so that reverting them would leave the organization over its quota, are skipped and reported.",
+	Args: cobra.NoArgs,
+	Run:  run,
+}
+
+func init() {
+	fs := Cmd.Flags()
+	fs.BoolVar(
+		&args.dryRun,
+		"dry-run",
+		false,
+		"If the dry-run is true, will only list the expired leases.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	environment, err := connection.Environment()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	// The lease file is locked until the reverted leases are saved, the exit releases it:
+	unlock, err := lease.Lock()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	defer unlock()
+	store, err := lease.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	// Only the leases of the environment are reverted, the others are left to the 'gc' of theirs:
+	now := time.Now()
+	var expired []*lease.Lease
+	for _, l := range store.Leases {
+		if !l.Expired(now) {
+			continue
+		}
+		if l.Environment != environment {
+			if l.Environment == "" {
+				fmt.Fprintf(os.Stderr, "[W] Skipped the lease %s of the %s_%s resource quota of '%s': "+
+					"it was recorded without an environment.\n", l.ID, l.Sku, l.Type, l.Username)
+			}
+			continue
+		}
+		expired = append(expired, l)
+	}
+	if args.dryRun || len(expired) == 0 {
+		if err = lease.FPrintLeases(expired, now); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	ctx := cmd.Context()
+	var reverted, skipped, failed int
+	for _, l := range expired {
+		if ctx.Err() != nil {
+			break
+		}
+		err = quota.RevertLease(ctx, l)
+		switch {
+		case errors.Is(err, quota.ErrInUse):
+			skipped++
+			fmt.Fprintf(os.Stderr, "[W] Skipped the lease %s of the %s_%s resource quota of '%s': %v.\n",
+				l.ID, l.Sku, l.Type, l.Username, err)
+		case err != nil:
+			failed++
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+		default:
+			reverted++
+			store.Delete(l.ID)
+			slog.InfoContext(ctx, "Reverted the lease", "lease", l.ID, "sku", l.Sku, "type", l.Type,
+				"username", l.Username, "owner", l.Owner, "reason", l.Reason)
+		}
+	}
+
+	if err = store.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Reverted %d, skipped %d and failed %d of %d expired leases.\n",
+		reverted, skipped, failed, len(expired))
+	if failed != 0 || ctx.Err() != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github/yasun1/myquota/pkg/connection"
+	"github/yasun1/myquota/pkg/history"
+
+	"github.com/spf13/cobra"
+)
+
+var args struct {
+	username string
+	window   time.Duration
+	summary  bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "history <skuIDs>",
+	Short: "Show the recorded usage of the quota under the account",
+	Long: "Show the usage of the quota recorded by 'myquota record' for the account over a window, " +
+		"followed by the peak consumption and the minimum headroom of each quota. " +
+		"The skuIDs may also be quota ids, etc, 'cluster|byoc|osd'. " +
+		"If no skuIDs are specified, will show all the recorded quota of the account.",
+	Run: run,
+}
+
+func init() {
+	fs := Cmd.Flags()
+	fs.StringVarP(
+		&args.username,
+		"username",
+		"u",
+		"",
+		"The username of the account.",
+	)
+	fs.DurationVar(
+		&args.window,
+		"window",
+		7*24*time.Hour,
+		"The window of the history, up to now.",
+	)
+	fs.BoolVar(
+		&args.summary,
+		"summary",
+		false,
+		"If the summary is true, will only show the peak consumption and the minimum headroom.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	if args.username == "" {
+		fmt.Fprintf(os.Stderr, "[E] The option '--username' is mandatory.\n\n")
+		os.Exit(1)
+	}
+
+	environment, err := connection.Environment()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	samples, err := history.Read(environment, args.username, time.Now().Add(-args.window), argv...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if len(samples) == 0 {
+		fmt.Fprintf(os.Stderr, "[E] No usage was recorded for the account '%s' in the last %s.\n",
+			args.username, args.window)
+		os.Exit(1)
+	}
+
+	if !args.summary {
+		err = history.FPrintSeries(samples)
+	}
+	if err == nil {
+		err = history.FPrintSummaries(history.Summarize(samples), args.window)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
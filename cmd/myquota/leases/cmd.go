@@ -0,0 +1,34 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leases
+
+import (
+	"github/yasun1/myquota/cmd/myquota/leases/list"
+
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "leases",
+	Short: "Manage the leases of the temporarily assigned resource quota",
+	Long: "Manage the leases recorded by 'myquota assign --ttl'. " +
+		"The expired leases are reverted by 'myquota gc'.",
+}
+
+func init() {
+	Cmd.AddCommand(list.Cmd)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package list
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github/yasun1/myquota/pkg/lease"
+
+	"github.com/spf13/cobra"
+)
+
+var args struct {
+	username string
+	expired  bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the leases of the resource quota",
+	Long:  "List the leases of the temporarily assigned resource quota, ordered by expiry.",
+	Args:  cobra.NoArgs,
+	Run:   run,
+}
+
+func init() {
+	fs := Cmd.Flags()
+	fs.StringVarP(
+		&args.username,
+		"username",
+		"u",
+		"",
+		"If the username is set, will only list the leases of the account.",
+	)
+	fs.BoolVar(
+		&args.expired,
+		"expired",
+		false,
+		"If the expired is true, will only list the expired leases.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	store, err := lease.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	now := time.Now()
+	var leases []*lease.Lease
+	for _, l := range store.Leases {
+		if args.username != "" && l.Username != args.username {
+			continue
+		}
+		if args.expired && !l.Expired(now) {
+			continue
+		}
+		leases = append(leases, l)
+	}
+
+	if err = lease.FPrintLeases(leases, now); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
@@ -17,23 +17,47 @@ limitations under the License.
 package list
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"regexp"
+	"strings"
+	"time"
 
+	"github/yasun1/myquota/pkg/group"
 	"github/yasun1/myquota/pkg/quota"
+	"github/yasun1/myquota/pkg/term"
 
 	"github.com/spf13/cobra"
 )
 
 var args struct {
-	username string
+	username     string
+	watch        bool
+	interval     time.Duration
+	sort         string
+	onlyUsed     bool
+	onlyAssigned bool
+	filter       string
+	color        string
 }
 
+// Values of the option '--color'
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
 var Cmd = &cobra.Command{
 	Use:   "list <skuIDs>",
 	Short: "List the quota cost under the account",
 	Long: "List the quota cost in the organization that the account is belonged to. " +
-		"If no skuIDs are specified, will list all the quota of the organization.",
+		"If no skuIDs are specified, will list all the quota of the organization. " +
+		"With the option '--watch', will refresh the list until interrupted. " +
+		"With the option '--group', will list the quota of every organization of the group in one table. " +
+		"The quota is sorted by name unless '--sort' is given, and can be filtered by '--only-used', " +
+		"'--only-assigned' and '--filter'.",
 	Run: run,
 }
 
@@ -46,34 +70,187 @@ func init() {
 		"",
 		"The username of the account.",
 	)
+	fs.BoolVarP(
+		&args.watch,
+		"watch",
+		"w",
+		false,
+		"If the watch is true, will poll the quota every interval, and show the changes. "+
+			"On a terminal the table is redrawn and the changed cells are highlighted, "+
+			"otherwise only the changed rows are written as timestamped events.",
+	)
+	fs.DurationVar(
+		&args.interval,
+		"interval",
+		10*time.Second,
+		"The time between the polls of the option '--watch'.",
+	)
+	fs.StringVar(
+		&args.sort,
+		"sort",
+		quota.SortName,
+		"The order of the quota: 'name', or from the highest 'allowed', 'consumed' or 'utilization'.",
+	)
+	fs.BoolVar(
+		&args.onlyUsed,
+		"only-used",
+		false,
+		"If the only-used is true, will only list the consumed quota.",
+	)
+	fs.BoolVar(
+		&args.onlyAssigned,
+		"only-assigned",
+		false,
+		"If the only-assigned is true, will only list the allowed quota.",
+	)
+	fs.StringVar(
+		&args.filter,
+		"filter",
+		"",
+		"The regular expression that the sku or the quota id must match, etc, '^MCT' or 'osd$'.",
+	)
+	fs.StringVar(
+		&args.color,
+		"color",
+		colorAuto,
+		"Whether to color the utilization of the near full quota: 'auto', 'always' or 'never'. "+
+			"With 'auto', the output is colored on a terminal, unless 'NO_COLOR' is set.",
+	)
+	group.AddFlags(fs)
 }
 
 func run(cmd *cobra.Command, argv []string) {
-	if args.username == "" {
+	switch {
+	case group.Name() != "" && args.username != "":
+		fmt.Fprintf(os.Stderr, "[E] The options '--username' and '--group' can't be used together.\n\n")
+		os.Exit(1)
+	case group.Name() != "" && args.watch:
+		fmt.Fprintf(os.Stderr, "[E] The options '--watch' and '--group' can't be used together.\n\n")
+		os.Exit(1)
+	case group.Name() == "" && args.username == "":
 		fmt.Fprintf(os.Stderr, "[E] The option '--username' is mandatory.\n\n")
 		os.Exit(1)
 	}
 
-	orgID, err := quota.GetOrgID(args.username)
+	filter := quota.UsageFilter{
+		OnlyUsed:     args.onlyUsed,
+		OnlyAssigned: args.onlyAssigned,
+	}
+	if args.filter != "" {
+		pattern, err := regexp.Compile(args.filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[E] The filter '%s' is invalid: %v\n", args.filter, err)
+			os.Exit(1)
+		}
+		filter.Pattern = pattern
+	}
+	if err := quota.ValidSort(args.sort); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	var color bool
+	switch args.color {
+	case colorAuto:
+		color = term.UseColor(os.Stdout)
+	case colorAlways:
+		color = true
+	case colorNever:
+	default:
+		fmt.Fprintf(os.Stderr, "[E] The color '%s' is invalid, valid values are '%s'.\n",
+			args.color, strings.Join([]string{colorAuto, colorAlways, colorNever}, "', '"))
+		os.Exit(1)
+	}
+
+	ctx := cmd.Context()
+	specifiedSKus, err := parseSkus(ctx, argv)
 	if err != nil {
-		fmt.Printf("%v\n", err)
-		os.Exit(0)
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	// usage returns the selected quota of the organization, in the order of '--sort':
+	usage := func(ctx context.Context, orgID string) ([]quota.Usage, error) {
+		var usages []quota.Usage
+		var err error
+		if len(specifiedSKus) == 0 {
+			usages, err = quota.OrgUsage(ctx, orgID)
+		} else {
+			usages, err = quota.SkuUsage(ctx, orgID, specifiedSKus...)
+		}
+		if err != nil {
+			return nil, err
+		}
+		usages = quota.FilterUsages(usages, filter)
+		return usages, quota.SortUsages(usages, args.sort)
+	}
+	if group.Name() != "" {
+		runGroup(ctx, usage, color)
+		return
 	}
 
-	if len(argv) == 0 {
-		quota.FPrintQuotaCost(orgID)
+	orgID, err := quota.GetOrgID(ctx, args.username)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	if args.watch {
+		if args.interval <= 0 {
+			fmt.Fprintf(os.Stderr, "[E] The interval must be positive.\n")
+			os.Exit(1)
+		}
+		orgUsage := func(ctx context.Context) ([]quota.Usage, error) {
+			return usage(ctx, orgID)
+		}
+		err = quota.Watch(ctx, os.Stdout, orgID, args.interval, term.IsTerminal(os.Stdout), color, orgUsage)
 	} else {
-		skuMap := quota.AllSkus()
+		var usages []quota.Usage
+		usages, err = usage(ctx, orgID)
+		if err == nil {
+			err = quota.FPrintUsages(orgID, usages, color)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
 
-		var specifiedSKus []quota.Sku
-		for _, skuName := range argv {
-			if _, existed := skuMap[skuName]; !existed {
-				panic(fmt.Errorf("[E] The sku '%s' is invalid\n", skuName))
-			}
+// parseSkus returns the specified skus, or nil if none is specified.
+func parseSkus(ctx context.Context, argv []string) ([]quota.Sku, error) {
+	if len(argv) == 0 {
+		return nil, nil
+	}
+	skuMap, err := quota.AllSkus(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-			specifiedSKus = append(specifiedSKus, skuMap[skuName])
+	var specifiedSKus []quota.Sku
+	for _, skuName := range argv {
+		if _, existed := skuMap[skuName]; !existed {
+			return nil, fmt.Errorf("[E] The sku '%s' is invalid", skuName)
 		}
 
-		quota.FPrintUsageForSkus(orgID, specifiedSKus...)
+		specifiedSKus = append(specifiedSKus, skuMap[skuName])
+	}
+	return specifiedSKus, nil
+}
+
+// runGroup lists the quota of every organization of the group.
+func runGroup(ctx context.Context, usage func(ctx context.Context, orgID string) ([]quota.Usage, error), color bool) {
+	members, err := group.Members()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	results := group.Run(ctx, members, func(ctx context.Context, member group.Member) ([]quota.Usage, error) {
+		return usage(ctx, member.OrgID)
+	})
+	if err = group.FPrintUsage(results, color); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if group.FPrintErrors(results) != 0 {
+		os.Exit(1)
 	}
 }
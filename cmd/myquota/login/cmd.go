@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package login
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github/yasun1/myquota/cmd/myquota/whoami"
+	"github/yasun1/myquota/pkg/config"
+	"github/yasun1/myquota/pkg/connection"
+	"github/yasun1/myquota/pkg/quota"
+
+	"github.com/spf13/cobra"
+)
+
+var args struct {
+	token        string
+	clientID     string
+	clientSecret string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "login",
+	Short: "Save the credentials in the profile",
+	Long: "Check the credentials and save them in the active profile of the configuration file, " +
+		"so that the next commands use them. " +
+		"The credentials are the token given by '--token', the client ID and secret given by " +
+		"'--client-id' and '--client-secret', or if none of them is set, the credentials selected by '--auth'.",
+	Args: cobra.NoArgs,
+	Run:  run,
+}
+
+func init() {
+	fs := Cmd.Flags()
+	fs.StringVar(
+		&args.token,
+		"token",
+		"",
+		"The offline token. Use '-' to read it from the standard input.",
+	)
+	fs.StringVar(
+		&args.clientID,
+		"client-id",
+		"",
+		"The client ID of the service account.",
+	)
+	fs.StringVar(
+		&args.clientSecret,
+		"client-secret",
+		"",
+		"The client secret of the service account. The default is the value of 'OCM_CLIENT_SECRET'.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	ctx := cmd.Context()
+
+	token := args.token
+	if token == "-" {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			fmt.Fprintf(os.Stderr, "[E] Failed to read the token from the standard input: %v\n", err)
+			os.Exit(1)
+		}
+		token = strings.TrimSpace(line)
+	}
+	secret := args.clientSecret
+	if args.clientID != "" && secret == "" {
+		secret = os.Getenv("OCM_CLIENT_SECRET")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	profile, conn, err := connection.Login(ctx, token, args.clientID, secret)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	identity, err := quota.WhoAmI(ctx, conn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	// The login only replaces the connection settings, the policy of the profile is kept:
+	name := config.ProfileName()
+	profile.Policy = cfg.Profile(name).Policy
+	*cfg.Profile(name) = *profile
+	if err = cfg.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	slog.InfoContext(ctx, "Saved the credentials in the profile", "profile", name, "username", identity.Username)
+
+	if err = quota.FPrintIdentity(identity); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	whoami.WarnIfNotQuotaAdmin(identity)
+}
@@ -17,66 +17,118 @@ limitations under the License.
 package main
 
 import (
-	"flag"
+	"context"
 	"fmt"
-	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
+	"github/yasun1/myquota/cmd/myquota/apply"
 	"github/yasun1/myquota/cmd/myquota/assign"
+	"github/yasun1/myquota/cmd/myquota/bundle"
+	"github/yasun1/myquota/cmd/myquota/check"
+	"github/yasun1/myquota/cmd/myquota/export"
+	"github/yasun1/myquota/cmd/myquota/exporter"
+	"github/yasun1/myquota/cmd/myquota/forecast"
+	"github/yasun1/myquota/cmd/myquota/gc"
+	"github/yasun1/myquota/cmd/myquota/history"
+	"github/yasun1/myquota/cmd/myquota/leases"
 	"github/yasun1/myquota/cmd/myquota/list"
+	"github/yasun1/myquota/cmd/myquota/login"
+	"github/yasun1/myquota/cmd/myquota/plan"
+	"github/yasun1/myquota/cmd/myquota/reconcile"
+	"github/yasun1/myquota/cmd/myquota/record"
 	"github/yasun1/myquota/cmd/myquota/remove"
+	"github/yasun1/myquota/cmd/myquota/report"
+	"github/yasun1/myquota/cmd/myquota/serve"
+	"github/yasun1/myquota/cmd/myquota/validate"
+	"github/yasun1/myquota/cmd/myquota/whoami"
+	"github/yasun1/myquota/cmd/myquota/with"
 	"github/yasun1/myquota/pkg/flags"
+	"github/yasun1/myquota/pkg/logs"
+	"github/yasun1/myquota/pkg/timeout"
 
-	_ "github.com/golang/glog"
 	"github.com/spf13/cobra"
-	// "github.com/spf13/pflag"
 )
 
 var root = &cobra.Command{
 	Use: "myquota",
 	Long: "Command line tool for manage ocm resource quotas." +
 		" The default stage is stage ocm, setting OCM_ENV=prod will change to prod ocm.",
+	PersistentPreRunE: setup,
+	SilenceErrors:     true,
 }
 
-func init() {
-	// Send logs to the standard error stream by default:
-	// err := flag.Set("logtostderr", "true")
-	// if err != nil {
-	// 	fmt.Fprintf(os.Stderr, "Can't set default error stream: %v\n", err)
-	// 	os.Exit(1)
-	// }
+// cancelTimeout releases the context created by setup.
+var cancelTimeout context.CancelFunc = func() {}
+
+// setup applies the global flags once they are parsed: it configures the logger, and bounds the
+// context of the executed command with the value of '--timeout'.
+func setup(cmd *cobra.Command, argv []string) error {
+	// The flags are valid at this point, so the usage doesn't help to understand the errors:
+	cmd.SilenceUsage = true
+
+	err := logs.Setup()
+	if err != nil {
+		return err
+	}
 
-	// Register the options that are managed by the 'flag' package, so that they will also be parsed
-	// by the 'pflag' package:
-	// pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
+	var ctx context.Context
+	ctx, cancelTimeout = timeout.WithTimeout(cmd.Context())
+	cmd.SetContext(ctx)
+	return nil
+}
 
+func init() {
 	// Add the command line flags:
 	fs := root.PersistentFlags()
+	flags.AddConnectionFlags(fs)
+	flags.AddConfigFlags(fs)
 	flags.AddDebugFlag(fs)
-
-	// Set log title
-	log.SetPrefix("[quota] ")
-	log.SetFlags(log.LstdFlags | log.LUTC)
+	flags.AddLogFlags(fs)
+	flags.AddTimeoutFlag(fs)
+	flags.AddHistoryFlag(fs)
+	flags.AddLeaseFlag(fs)
+	flags.AddAuditFlag(fs)
 
 	// Register the subcommands:
 	root.AddCommand(assign.Cmd)
 	root.AddCommand(remove.Cmd)
 	root.AddCommand(list.Cmd)
+	root.AddCommand(check.Cmd)
+	root.AddCommand(exporter.Cmd)
+	root.AddCommand(record.Cmd)
+	root.AddCommand(history.Cmd)
+	root.AddCommand(forecast.Cmd)
+	root.AddCommand(with.Cmd)
+	root.AddCommand(leases.Cmd)
+	root.AddCommand(gc.Cmd)
+	root.AddCommand(serve.Cmd)
+	root.AddCommand(bundle.Cmd)
+	root.AddCommand(export.Cmd)
+	root.AddCommand(validate.Cmd)
+	root.AddCommand(plan.Cmd)
+	root.AddCommand(apply.Cmd)
+	root.AddCommand(reconcile.Cmd)
+	root.AddCommand(report.Cmd)
+	root.AddCommand(login.Cmd)
+	root.AddCommand(whoami.Cmd)
 }
 
 func main() {
-	// This is needed to make `glog` believe that the flags have already been parsed, otherwise
-	// every log messages is prefixed by an error message stating the the flags haven't been
-	// parsed.
-	err := flag.CommandLine.Parse([]string{})
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Can't parse empty command line to satisfy 'glog': %v\n", err)
-		os.Exit(1)
-	}
+	// Cancel the running command on the first interrupt, so that it can report how far it got.
+	// A second interrupt terminates the process immediately:
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
 
 	// Execute the root command:
 	root.SetArgs(os.Args[1:])
-	err = root.Execute()
+	err := root.ExecuteContext(ctx)
+	cancelTimeout()
+	stop()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to execute root command: %v\n", err)
 		os.Exit(1)
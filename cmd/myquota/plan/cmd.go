@@ -0,0 +1,137 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github/yasun1/myquota/pkg/connection"
+	"github/yasun1/myquota/pkg/manifest"
+	"github/yasun1/myquota/pkg/quota"
+
+	"github.com/spf13/cobra"
+)
+
+var args struct {
+	file    string
+	overlay string
+	vars    []string
+	prune   bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Show the changes to reach the quota manifest",
+	Long: "Compare the quota manifest with the resource quotas of its organizations, and show the changes " +
+		"that 'myquota apply' would make. The overlay of the active environment, etc, 'quota.staging.yaml' " +
+		"for 'quota.yaml', overrides the counts and the accounts of the manifest, adds or removes skus. " +
+		"The references '${VAR}' in the manifest and the overlay are replaced with the value given by " +
+		"'--set VAR=value', or with the environment variable. " +
+		"The command exits with 2 if there are changes.",
+	Args: cobra.NoArgs,
+	Run:  run,
+}
+
+func init() {
+	AddFlags(Cmd, &args.file, &args.overlay, &args.vars, &args.prune)
+}
+
+// AddFlags adds the flags that select the manifest, shared by 'plan' and 'apply'.
+func AddFlags(cmd *cobra.Command, file *string, overlay *string, vars *[]string, prune *bool) {
+	fs := cmd.Flags()
+	fs.StringVarP(
+		file,
+		"file",
+		"f",
+		"",
+		"The base manifest file.",
+	)
+	fs.StringVar(
+		overlay,
+		"overlay",
+		"",
+		"The overlay file. The default is the file next to the manifest named after the active environment, "+
+			"etc, 'quota.staging.yaml', if it exists.",
+	)
+	fs.StringArrayVar(
+		vars,
+		"set",
+		nil,
+		"Set the variable referenced as '${VAR}' in the manifests, etc, 'VAR=value'. Can be repeated.",
+	)
+	fs.BoolVar(
+		prune,
+		"prune",
+		false,
+		"If the prune is true, will also delete the resource quotas that aren't declared, of the types that the "+
+			"organization declares.",
+	)
+}
+
+// Load loads the manifest with the overlay of the active environment, and prints its problems.
+func Load(file string, overlay string, assignments []string) (*manifest.Manifest, error) {
+	if file == "" {
+		return nil, errors.New("[E] The option '--file' is mandatory.")
+	}
+	vars, err := manifest.ParseVars(assignments)
+	if err != nil {
+		return nil, err
+	}
+	environment, err := connection.ProfileEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	if overlay == "" {
+		overlay = manifest.OverlayPath(file, environment)
+	}
+	m, issues, err := manifest.Load(file, overlay, environment, vars)
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range issues {
+		fmt.Fprintln(os.Stderr, issue)
+	}
+	if len(issues) != 0 {
+		return nil, fmt.Errorf("[E] Found %d problems in the manifest.", len(issues))
+	}
+	slog.Info("Loaded the manifest", "file", file, "overlay", overlay, "environment", environment)
+	return m, nil
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	m, err := Load(args.file, args.overlay, args.vars)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	changes, err := quota.Plan(cmd.Context(), m, args.prune)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if err = quota.FPrintChanges(changes); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if len(changes) != 0 {
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github/yasun1/myquota/pkg/manifest"
+	"github/yasun1/myquota/pkg/reconcile"
+
+	"github.com/spf13/cobra"
+)
+
+var args struct {
+	dir        string
+	interval   time.Duration
+	reportOnly bool
+	prune      bool
+	vars       []string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Keep the resource quotas in the state declared by the manifests",
+	Long: "Watch a directory of quota manifests, and plan them every interval and whenever a manifest changes. " +
+		"The drift of the resource quotas is corrected like 'myquota apply' does, and every correction is " +
+		"recorded in the audit log. The overlays of the active environment are used, as for 'myquota plan'. " +
+		"The resource quotas in use are never deleted. With the option '--report-only', the drift is only logged.",
+	Args: cobra.NoArgs,
+	Run:  run,
+}
+
+func init() {
+	fs := Cmd.Flags()
+	fs.StringVar(
+		&args.dir,
+		"dir",
+		"",
+		"The directory of the manifests.",
+	)
+	fs.DurationVar(
+		&args.interval,
+		"interval",
+		10*time.Minute,
+		"The time between the reconciliations, when no manifest changes.",
+	)
+	fs.BoolVar(
+		&args.reportOnly,
+		"report-only",
+		false,
+		"If the report-only is true, will only log the drift without correcting it.",
+	)
+	fs.BoolVar(
+		&args.prune,
+		"prune",
+		false,
+		"If the prune is true, will also delete the resource quotas that aren't declared, of the types that the "+
+			"organization declares.",
+	)
+	fs.StringArrayVar(
+		&args.vars,
+		"set",
+		nil,
+		"Set the variable referenced as '${VAR}' in the manifests, etc, 'VAR=value'. Can be repeated.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	if args.dir == "" {
+		fmt.Fprintf(os.Stderr, "[E] The option '--dir' is mandatory.\n")
+		os.Exit(1)
+	}
+	if args.interval <= 0 {
+		fmt.Fprintf(os.Stderr, "[E] The interval must be positive.\n")
+		os.Exit(1)
+	}
+	vars, err := manifest.ParseVars(args.vars)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	ctx := cmd.Context()
+	slog.InfoContext(ctx, "Reconciling the manifests", "dir", args.dir, "interval", args.interval,
+		"report_only", args.reportOnly)
+	r := reconcile.New(args.dir, args.interval, args.reportOnly, args.prune, vars)
+	if err = r.Run(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package record
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github/yasun1/myquota/pkg/connection"
+	"github/yasun1/myquota/pkg/history"
+	"github/yasun1/myquota/pkg/quota"
+
+	"github.com/spf13/cobra"
+)
+
+var args struct {
+	usernames []string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "record",
+	Short: "Record the usage of the quota to the history file",
+	Long: "Sample the allowed and consumed quota in the organizations that the accounts are belonged to, " +
+		"and append the samples to the history file. It is meant to be run periodically, etc, by cron.",
+	Run: run,
+}
+
+func init() {
+	fs := Cmd.Flags()
+	fs.StringSliceVarP(
+		&args.usernames,
+		"username",
+		"u",
+		nil,
+		"The usernames of the accounts, separated by commas or repeated.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	if len(args.usernames) == 0 {
+		fmt.Fprintf(os.Stderr, "[E] The option '--username' is mandatory.\n")
+		os.Exit(1)
+	}
+
+	environment, err := connection.Environment()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	ctx := cmd.Context()
+	now := time.Now().UTC()
+	var samples []history.Sample
+	var failed int
+	for _, username := range args.usernames {
+		orgID, err := quota.GetOrgID(ctx, username)
+		if err == nil {
+			var usages []quota.Usage
+			usages, err = quota.OrgUsage(ctx, orgID)
+			for _, usage := range usages {
+				samples = append(samples, history.Sample{
+					Time:        now,
+					Environment: environment,
+					Username:    username,
+					OrgID:       orgID,
+					QuotaID:     usage.QuotaID,
+					SkuNames:    usage.SkuNames,
+					Allowed:     usage.Allowed,
+					Consumed:    usage.Consumed,
+				})
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			failed++
+		}
+	}
+
+	if err := history.Append(samples); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	slog.InfoContext(ctx, "Recorded the usage of the quota", "samples", len(samples),
+		"users", len(args.usernames)-failed)
+	if failed != 0 {
+		fmt.Fprintf(os.Stderr, "[E] Failed to record the usage of %d of %d accounts.\n", failed, len(args.usernames))
+		os.Exit(1)
+	}
+}
@@ -17,9 +17,13 @@ limitations under the License.
 package remove
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"text/tabwriter"
 
+	"github/yasun1/myquota/pkg/group"
 	"github/yasun1/myquota/pkg/quota"
 
 	"github.com/spf13/cobra"
@@ -32,10 +36,13 @@ var args struct {
 }
 
 var Cmd = &cobra.Command{
-	Use:   "remove <skuID>",
+	Use:   "remove <skuID>...",
 	Short: "Remove the 'Manual' resource quota under the account",
-	Long:  "Remove the 'Manual' resource quota from the organization that the account is belonged to.",
-	Run:   run,
+	Long: "Remove the 'Manual' resource quota from the organization that the account is belonged to. " +
+		"If several skuIDs are specified, they are removed one by one and the progress is reported " +
+		"when the command is interrupted. " +
+		"With the option '--group', will remove the resource quota from every organization of the group.",
+	Run: run,
 }
 
 func init() {
@@ -61,18 +68,28 @@ func init() {
 		false,
 		"If the force is true, will ignore checking the consumed quota and forcely remove the quota from the organization.",
 	)
+	group.AddFlags(fs)
 }
 
 func run(cmd *cobra.Command, argv []string) {
-	if args.username == "" {
+	switch {
+	case group.Name() != "" && args.username != "":
+		fmt.Fprintf(os.Stderr, "[E] The options '--username' and '--group' can't be used together.\n\n")
+		os.Exit(1)
+	case group.Name() == "" && args.username == "":
 		fmt.Fprintf(os.Stderr, "[E] The option '--username' is mandatory.\n\n")
 		os.Exit(1)
 	}
 
-	orgID, err := quota.GetOrgID(args.username)
-	if err != nil {
-		fmt.Printf("%v\n", err)
-		os.Exit(0)
+	ctx := cmd.Context()
+	var orgID string
+	var err error
+	if group.Name() == "" {
+		orgID, err = quota.GetOrgID(ctx, args.username)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
 	}
 
 	if len(argv) == 0 {
@@ -80,14 +97,79 @@ func run(cmd *cobra.Command, argv []string) {
 		os.Exit(1)
 	}
 
-	skuName := argv[0]
-	skuMap := quota.AllSkus()
-	if _, existed := skuMap[skuName]; !existed {
-		panic(fmt.Errorf("[E] The input sku '%s' is invalid\n", skuName))
+	skuMap, err := quota.AllSkus(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	var skus []quota.Sku
+	for _, skuName := range argv {
+		if _, existed := skuMap[skuName]; !existed {
+			fmt.Fprintf(os.Stderr, "[E] The input sku '%s' is invalid.\n", skuName)
+			os.Exit(1)
+		}
+		sku := skuMap[skuName]
+		sku.Type = args.qtype
+		skus = append(skus, sku)
+	}
+
+	if group.Name() != "" {
+		runGroup(ctx, skus)
+		return
 	}
-	sku := skuMap[skuName]
-	sku.Type = args.qtype
 
 	// Remove the quota
-	quota.RemoveQuota(orgID, sku, args.force)
+	removed, err := quota.RemoveQuotas(ctx, orgID, args.force, skus...)
+	if errors.Is(err, quota.ErrInUse) {
+		// Show how much of the quota is in use:
+		if printErr := quota.FPrintUsageForSkus(ctx, orgID, skus[removed]); printErr != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", printErr)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fmt.Fprintf(os.Stderr, "[E] Stopped after removing %d of %d resource quotas.\n", removed, len(skus))
+		os.Exit(1)
+	}
+}
+
+// runGroup removes the resource quota from every organization of the group, and prints how many
+// resource quotas were removed from each of them.
+func runGroup(ctx context.Context, skus []quota.Sku) {
+	members, err := group.Members()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	results := group.Run(ctx, members, func(ctx context.Context, member group.Member) (int, error) {
+		removed, err := quota.RemoveQuotas(ctx, member.OrgID, args.force, skus...)
+		if err != nil {
+			return removed, fmt.Errorf("[E] Stopped after removing %d of %d resource quotas: %w", removed, len(skus), err)
+		}
+		return removed, nil
+	})
+
+	fmt.Printf("\n>>> The removed resource quotas of the group %s: \n", group.Name())
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 8, ' ', 0)
+	fmt.Fprintf(writer, "Org\tRemoved\tStatus\t\n")
+	for _, result := range results {
+		status := "done"
+		if result.Err != nil {
+			status = "failed"
+		}
+		fmt.Fprintf(writer, "%s\t%d/%d\t%s\n",
+			result.Label(),
+			result.Value,
+			len(skus),
+			status,
+		)
+	}
+	if err = writer.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if group.FPrintErrors(results) != 0 {
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,35 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"github/yasun1/myquota/cmd/myquota/report/org"
+	"github/yasun1/myquota/cmd/myquota/report/sku"
+
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "report",
+	Short: "Report the quota across the organizations",
+	Long:  "Report the quota of several organizations, or of an organization in a document format.",
+}
+
+func init() {
+	Cmd.AddCommand(sku.Cmd)
+	Cmd.AddCommand(org.Cmd)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package org
+
+import (
+	"fmt"
+	"os"
+
+	"github/yasun1/myquota/pkg/connection"
+	"github/yasun1/myquota/pkg/quota"
+	"github/yasun1/myquota/pkg/report"
+
+	"github.com/spf13/cobra"
+)
+
+var args struct {
+	username string
+	format   string
+	warn     float64
+}
+
+var Cmd = &cobra.Command{
+	Use:   "org",
+	Short: "Report the quota of the organization in Markdown or HTML",
+	Long: "Write a report of the quota in the organization that the account is belonged to, in Markdown or HTML, " +
+		"to paste in the documents. The report has the skus grouped by quota id, the utilization of each quota " +
+		"with the near full ones highlighted, and the environment, the time and the organization it was generated " +
+		"for. The quotas that are neither allowed nor consumed are left out.",
+	Args: cobra.NoArgs,
+	Run:  run,
+}
+
+func init() {
+	fs := Cmd.Flags()
+	fs.StringVarP(
+		&args.username,
+		"username",
+		"u",
+		"",
+		"The username of the account.",
+	)
+	fs.StringVar(
+		&args.format,
+		"format",
+		report.FormatMarkdown,
+		"The format of the report, 'markdown' or 'html'.",
+	)
+	fs.Float64Var(
+		&args.warn,
+		"warn",
+		80,
+		"The utilization percentage from which the quota is highlighted as near full.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	if args.username == "" {
+		fmt.Fprintf(os.Stderr, "[E] The option '--username' is mandatory.\n\n")
+		os.Exit(1)
+	}
+	if args.format != report.FormatMarkdown && args.format != report.FormatHTML {
+		fmt.Fprintf(os.Stderr, "[E] The format '%s' is invalid, valid formats are '%s' and '%s'.\n",
+			args.format, report.FormatMarkdown, report.FormatHTML)
+		os.Exit(1)
+	}
+
+	ctx := cmd.Context()
+	orgID, err := quota.GetOrgID(ctx, args.username)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	usages, err := quota.OrgUsage(ctx, orgID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	environment, err := connection.Environment()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	r := report.New(environment, args.username, orgID, args.warn, usages)
+	if err = r.Write(os.Stdout, args.format); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
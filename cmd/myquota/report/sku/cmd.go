@@ -0,0 +1,104 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sku
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github/yasun1/myquota/pkg/group"
+	"github/yasun1/myquota/pkg/quota"
+
+	"github.com/spf13/cobra"
+)
+
+var args struct {
+	usersFile string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "sku <skuID>",
+	Short: "Report the organizations that hold the sku",
+	Long: "Query the resource quotas and the quota cost of every organization of the group, or of the " +
+		"accounts of the users file, and show the organizations that hold the sku, with the type, the count, " +
+		"the allowed and consumed quota, and the totals.",
+	Args: cobra.ExactArgs(1),
+	Run:  run,
+}
+
+func init() {
+	fs := Cmd.Flags()
+	group.AddFlags(fs)
+	fs.StringVar(
+		&args.usersFile,
+		"users-file",
+		"",
+		"The file of the usernames of the accounts, one per line.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	var members []group.Member
+	var err error
+	switch {
+	case group.Name() != "" && args.usersFile != "":
+		fmt.Fprintf(os.Stderr, "[E] The options '--group' and '--users-file' can't be used together.\n\n")
+		os.Exit(1)
+	case group.Name() != "":
+		members, err = group.Members()
+	case args.usersFile != "":
+		members, err = group.ReadMembers(args.usersFile)
+	default:
+		fmt.Fprintf(os.Stderr, "[E] The option '--group' or '--users-file' is mandatory.\n\n")
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	ctx := cmd.Context()
+	skuMap, err := quota.AllSkus(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	sku, existed := skuMap[argv[0]]
+	if !existed {
+		fmt.Fprintf(os.Stderr, "[E] The sku '%s' is invalid.\n", argv[0])
+		os.Exit(1)
+	}
+
+	results := group.Run(ctx, members, func(ctx context.Context, member group.Member) ([]quota.SkuHolding, error) {
+		return quota.SkuHoldings(ctx, member.OrgID, sku)
+	})
+	var holdings []quota.SkuHolding
+	for _, result := range results {
+		for _, holding := range result.Value {
+			holding.Org = result.Label()
+			holdings = append(holdings, holding)
+		}
+	}
+	if err = quota.FPrintSkuHoldings(sku, holdings, len(results)); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if group.FPrintErrors(results) != 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github/yasun1/myquota/pkg/audit"
+	"github/yasun1/myquota/pkg/config"
+	"github/yasun1/myquota/pkg/server"
+
+	"github.com/spf13/cobra"
+)
+
+// shutdownTimeout is how long the requests in progress are waited for when the server stops.
+const shutdownTimeout = 30 * time.Second
+
+var args struct {
+	listen  string
+	tlsCert string
+	tlsKey  string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a REST API to read and change the quota with API keys",
+	Long: "Serve a REST API that reads the usage, assigns and removes the resource quota with the credentials " +
+		"of the tool, for the holders of the API keys of the configuration file. Each API key is limited to " +
+		"some organizations, and to a maximum count of some skus. Every call is recorded in the audit file.",
+	Args: cobra.NoArgs,
+	Run:  run,
+}
+
+func init() {
+	fs := Cmd.Flags()
+	fs.StringVar(
+		&args.listen,
+		"listen",
+		":8080",
+		"The address the API is served on.",
+	)
+	fs.StringVar(
+		&args.tlsCert,
+		"tls-cert",
+		"",
+		"The certificate of the server, in PEM format. If it is set, the API is served with HTTPS.",
+	)
+	fs.StringVar(
+		&args.tlsKey,
+		"tls-key",
+		"",
+		"The private key of the certificate of the server, in PEM format.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	if (args.tlsCert == "") != (args.tlsKey == "") {
+		fmt.Fprintf(os.Stderr, "[E] The options '--tls-cert' and '--tls-key' must be set together.\n")
+		os.Exit(1)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if len(cfg.APIKeys) == 0 {
+		fmt.Fprintf(os.Stderr, "[E] No API keys in the configuration file, nobody could call the API.\n")
+		os.Exit(1)
+	}
+	handler, err := server.New(cfg.APIKeys)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	auditPath, err := audit.Path()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	ctx := cmd.Context()
+	httpServer := &http.Server{
+		Addr:              args.listen,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		// The requests are detached from the signals, so that the changes in progress aren't
+		// interrupted half way, and are drained by the shutdown instead:
+		BaseContext: func(_ net.Listener) context.Context {
+			return context.Background()
+		},
+	}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			slog.WarnContext(ctx, "Stopped the server before the requests in progress completed", "error", err)
+		}
+	}()
+
+	slog.InfoContext(ctx, "Serving the API", "listen", args.listen, "tls", args.tlsCert != "",
+		"api_keys", len(cfg.APIKeys), "audit_file", auditPath)
+	if args.tlsCert != "" {
+		err = httpServer.ListenAndServeTLS(args.tlsCert, args.tlsKey)
+	} else {
+		err = httpServer.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "[E] Failed to serve the API: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Wait for the requests in progress, 'ListenAndServe' returns as soon as the shutdown starts:
+	<-stopped
+}
@@ -0,0 +1,167 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github/yasun1/myquota/pkg/config"
+	"github/yasun1/myquota/pkg/connection"
+	"github/yasun1/myquota/pkg/manifest"
+	"github/yasun1/myquota/pkg/quota"
+
+	"github.com/spf13/cobra"
+)
+
+var args struct {
+	files       []string
+	vars        []string
+	online      bool
+	printSchema bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the quota manifests",
+	Long: "Check the quota manifests against the JSON Schema of the manifests, without connecting to OCM: " +
+		"the types, the non-negative counts, the known environments, and the duplicated sku and type in an " +
+		"organization. The variables are interpolated first, and the overlays, etc, 'quota.staging.yaml' " +
+		"next to 'quota.yaml', are checked as overlays. " +
+		"With the option '--online', will also check the skus and the usernames in OCM. " +
+		"The problems are reported with their file and line, and the command exits with 1 if there are any.",
+	Args: cobra.NoArgs,
+	Run:  run,
+}
+
+func init() {
+	fs := Cmd.Flags()
+	fs.StringSliceVarP(
+		&args.files,
+		"file",
+		"f",
+		nil,
+		"The manifest files, separated by commas or repeated.",
+	)
+	fs.StringArrayVar(
+		&args.vars,
+		"set",
+		nil,
+		"Set the variable referenced as '${VAR}' in the manifests, etc, 'VAR=value'. Can be repeated.",
+	)
+	fs.BoolVar(
+		&args.online,
+		"online",
+		false,
+		"If the online is true, will also check that the skus and the usernames exist in OCM.",
+	)
+	fs.BoolVar(
+		&args.printSchema,
+		"print-schema",
+		false,
+		"If the print-schema is true, will only print the JSON Schema of the manifests.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	if args.printSchema {
+		os.Stdout.Write(manifest.Schema)
+		return
+	}
+	if len(args.files) == 0 {
+		fmt.Fprintf(os.Stderr, "[E] The option '--file' is mandatory.\n\n")
+		os.Exit(1)
+	}
+
+	vars, err := manifest.ParseVars(args.vars)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	ctx := cmd.Context()
+	// Without '--online' there is no gateway, and the overlays are named after the profile:
+	active := config.ProfileName()
+	if args.online {
+		if active, err = connection.ProfileEnvironment(); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+	var skuMap map[string]quota.Sku
+	var issues int
+	for _, file := range args.files {
+		overlay := manifest.BasePath(file, active) != ""
+		m, fileIssues, err := manifest.ReadFile(file, vars, overlay)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		if len(fileIssues) == 0 && args.online {
+			if skuMap == nil {
+				if skuMap, err = quota.AllSkus(ctx); err != nil {
+					fmt.Fprintf(os.Stderr, "%v\n", err)
+					os.Exit(1)
+				}
+			}
+			fileIssues = checkOnline(ctx, file, m, skuMap)
+		}
+		for _, issue := range fileIssues {
+			fmt.Println(issue)
+		}
+		issues += len(fileIssues)
+	}
+
+	if issues != 0 {
+		fmt.Fprintf(os.Stderr, "[E] Found %d problems in %d manifests.\n", issues, len(args.files))
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "No problems found in %d manifests.\n", len(args.files))
+}
+
+// checkOnline checks that the skus exist, and that the usernames exist and belong to the declared
+// organizations.
+func checkOnline(ctx context.Context, file string, m *manifest.Manifest, skuMap map[string]quota.Sku) []manifest.Issue {
+	var issues []manifest.Issue
+	issue := func(position manifest.Position, format string, a ...interface{}) {
+		issues = append(issues, manifest.Issue{
+			File:    file,
+			Line:    position.Line,
+			Column:  position.Column,
+			Message: fmt.Sprintf(format, a...),
+		})
+	}
+	for i, org := range m.Orgs {
+		if org.Username != "" {
+			orgID, err := quota.GetOrgID(ctx, org.Username)
+			switch {
+			case err != nil:
+				issue(org.Position, "orgs[%d]: %v", i, err)
+			case org.OrgID != "" && org.OrgID != orgID:
+				issue(org.Position, "orgs[%d]: the account '%s' belongs to the organization '%s', not '%s'",
+					i, org.Username, orgID, org.OrgID)
+			}
+		}
+		for j, q := range org.Quotas {
+			if _, existed := skuMap[q.Sku]; !existed {
+				issue(q.Position, "orgs[%d].quotas[%d]: the sku '%s' doesn't exist in OCM", i, j, q.Sku)
+			}
+		}
+	}
+	return issues
+}
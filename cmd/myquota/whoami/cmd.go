@@ -0,0 +1,69 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package whoami
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github/yasun1/myquota/pkg/connection"
+	"github/yasun1/myquota/pkg/quota"
+
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Print the account the tool is acting as",
+	Long: "Print the username, the organization, the roles and the gateway URL of the account " +
+		"the tool is acting as, and warn if the account isn't allowed to manage the resource quotas.",
+	Args: cobra.NoArgs,
+	Run:  run,
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	ctx := cmd.Context()
+	conn, err := connection.SuperAdminConnection()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	identity, err := quota.WhoAmI(ctx, conn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if err = quota.FPrintIdentity(identity); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	WarnIfNotQuotaAdmin(identity)
+}
+
+// WarnIfNotQuotaAdmin warns when the account can't manage the resource quotas.
+func WarnIfNotQuotaAdmin(identity *quota.Identity) {
+	switch {
+	case identity.QuotaAdminErr != nil:
+		slog.Warn("Can't check whether the account is allowed to manage the resource quotas",
+			"error", identity.QuotaAdminErr)
+	case !identity.QuotaAdmin:
+		slog.Warn("The account is not allowed to manage the resource quotas, the 'assign' and 'remove' commands will fail",
+			"username", identity.Username, "url", identity.URL)
+	}
+}
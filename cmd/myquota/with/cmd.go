@@ -0,0 +1,254 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package with
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github/yasun1/myquota/pkg/quota"
+	"github/yasun1/myquota/pkg/term"
+
+	"github.com/spf13/cobra"
+)
+
+// restoreTimeout bounds the restoration of the resource quotas, which runs even after the command
+// is interrupted or timed out.
+const restoreTimeout = 2 * time.Minute
+
+var args struct {
+	username string
+	qtype    string
+	number   int
+}
+
+var Cmd = &cobra.Command{
+	Use:   "with <skuID>[=<number>]... -- <command> [<args>...]",
+	Short: "Assign the resource quota for the duration of a command",
+	Long: "Assign the resource quota to the account, run the command, and restore the resource quota " +
+		"to its previous state when the command exits, fails or is interrupted. " +
+		"Each skuID is assigned the number given after '=', or the value of the option '--number'. " +
+		"On a terminal, the command runs in the foreground, and the signals received are forwarded to it. " +
+		"Otherwise it shares the signals of the process group of myquota. The exit code of the command is returned.",
+	Run: run,
+}
+
+func init() {
+	fs := Cmd.Flags()
+	fs.StringVarP(
+		&args.username,
+		"username",
+		"u",
+		"",
+		"The username of the account.",
+	)
+	fs.StringVarP(
+		&args.qtype,
+		"qtype",
+		"t",
+		"Manual",
+		"The type of the quota.",
+	)
+	fs.IntVarP(
+		&args.number,
+		"number",
+		"n",
+		0,
+		"The number is the applied sku account, unless it is given after the skuID.",
+	)
+}
+
+// parseSkus returns the skus of the arguments, with their number.
+func parseSkus(skuMap map[string]quota.Sku, argv []string) ([]quota.Sku, error) {
+	var skus []quota.Sku
+	seen := make(map[string]bool)
+	for _, arg := range argv {
+		skuName, number, hasNumber := strings.Cut(arg, "=")
+		sku, existed := skuMap[skuName]
+		if !existed {
+			return nil, fmt.Errorf("[E] The input sku '%s' is invalid", skuName)
+		}
+		if seen[skuName] {
+			return nil, fmt.Errorf("[E] The sku '%s' is specified more than once", skuName)
+		}
+		seen[skuName] = true
+
+		sku.Allowed = args.number
+		if hasNumber {
+			count, err := strconv.Atoi(number)
+			if err != nil || count < 0 {
+				return nil, fmt.Errorf("[E] The number of the sku '%s' is invalid: '%s'", skuName, number)
+			}
+			sku.Allowed = count
+		}
+		sku.Type = args.qtype
+		skus = append(skus, sku)
+	}
+	return skus, nil
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	if args.username == "" {
+		fmt.Fprintf(os.Stderr, "[E] The option '--username' is mandatory.\n\n")
+		os.Exit(1)
+	}
+	dash := cmd.ArgsLenAtDash()
+	if dash < 0 || dash == len(argv) {
+		fmt.Fprintf(os.Stderr, "[E] The command is required after '--'.\n\n")
+		os.Exit(1)
+	}
+	if dash == 0 {
+		fmt.Fprintf(os.Stderr, "[E] The sku id is required.\n\n")
+		os.Exit(1)
+	}
+	command := argv[dash:]
+
+	ctx := cmd.Context()
+	orgID, err := quota.GetOrgID(ctx, args.username)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	skuMap, err := quota.AllSkus(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	skus, err := parseSkus(skuMap, argv[:dash])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	snapshot, err := quota.TakeSnapshot(ctx, orgID, skus...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	// Catch the signals before changing anything, so that the resource quota is always restored:
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	exitCode := 1
+	applied, err := quota.AssignQuotas(ctx, orgID, skus...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fmt.Fprintf(os.Stderr, "[E] Stopped after assigning %d of %d resource quotas.\n", applied, len(skus))
+	} else {
+		exitCode = runCommand(ctx, command, signals)
+	}
+
+	// The context may be cancelled already, but the resource quota must be restored anyway:
+	restoreCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), restoreTimeout)
+	defer cancel()
+	if err = snapshot.Restore(restoreCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fmt.Fprintf(os.Stderr, "[E] Failed to restore the resource quota of the organization %s.\n", orgID)
+		if exitCode == 0 {
+			exitCode = 1
+		}
+	}
+	os.Exit(exitCode)
+}
+
+// runCommand runs the command until it exits, and returns its exit code. When the context
+// expires, the command is terminated.
+//
+// On an interactive terminal, the command runs in its own process group in the foreground, so
+// that it can read the terminal and gets its interrupts, and the signals received are forwarded
+// to it. Otherwise it stays in the process group of myquota, that catches the same signals to
+// restore the resource quota after the command exits, so they aren't forwarded.
+func runCommand(ctx context.Context, command []string, signals <-chan os.Signal) int {
+	child := exec.Command(command[0], command[1:]...)
+	child.Stdin = os.Stdin
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+	foreground := term.IsForeground(os.Stdin)
+	if foreground {
+		child.SysProcAttr = &syscall.SysProcAttr{Foreground: true, Ctty: int(os.Stdin.Fd())}
+	}
+	if err := child.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "[E] Failed to run the command '%s': %v\n", command[0], err)
+		if errors.Is(err, exec.ErrNotFound) {
+			return 127
+		}
+		return 126
+	}
+	if foreground {
+		defer func() {
+			if err := term.TakeForeground(os.Stdin); err != nil {
+				slog.WarnContext(ctx, "Failed to take the terminal back from the command", "error", err)
+			}
+		}()
+	}
+	slog.InfoContext(ctx, "Running the command", "command", command, "pid", child.Process.Pid)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- child.Wait()
+	}()
+
+	ctxDone := ctx.Done()
+	for {
+		select {
+		case sig := <-signals:
+			if !foreground {
+				slog.InfoContext(ctx, "The command receives the signal with its process group", "signal", sig)
+				continue
+			}
+			slog.InfoContext(ctx, "Forwarding the signal to the command", "signal", sig)
+			child.Process.Signal(sig)
+		case <-ctxDone:
+			// The interrupts are forwarded already, only the timeout terminates the command:
+			ctxDone = nil
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				slog.WarnContext(ctx, "Timed out, terminating the command")
+				child.Process.Signal(syscall.SIGTERM)
+			}
+		case err := <-done:
+			return exitCode(err)
+		}
+	}
+}
+
+// exitCode returns the exit code of the command, following the shell convention of 128 plus the
+// number of the signal for the commands killed by a signal.
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if err == nil {
+		return 0
+	}
+	if !errors.As(err, &exitErr) {
+		fmt.Fprintf(os.Stderr, "[E] Failed to wait for the command: %v\n", err)
+		return 1
+	}
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+	return exitErr.ExitCode()
+}
@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+var file string
+
+// AddFlag adds the '--audit-file' flag to the given set of command line flags.
+func AddFlag(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&file,
+		"audit-file",
+		os.Getenv("MYQUOTA_AUDIT"),
+		"The file the audit events are appended to, in JSON lines. The default is the value of "+
+			"'MYQUOTA_AUDIT', or 'myquota/audit.jsonl' in the user configuration directory.",
+	)
+}
+
+// Path returns the location of the audit file.
+func Path() (string, error) {
+	if file != "" {
+		return file, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("[E] Failed to find the configuration directory: %w", err)
+	}
+	return filepath.Join(configDir, "myquota", "audit.jsonl"), nil
+}
+
+// Outcomes of the audited actions
+const (
+	OutcomeSuccess = "success"
+	OutcomeDenied  = "denied"
+	OutcomeFailure = "failure"
+	// OutcomeIntent is recorded before applying a change, and is followed by the event of its result.
+	OutcomeIntent = "intent"
+)
+
+// Event is an audited action.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Actor   string    `json:"actor"`
+	Source  string    `json:"source,omitempty"`
+	Action  string    `json:"action"`
+	OrgID   string    `json:"org_id,omitempty"`
+	Sku     string    `json:"sku,omitempty"`
+	Type    string    `json:"type,omitempty"`
+	Count   *int      `json:"count,omitempty"`
+	Force   bool      `json:"force,omitempty"`
+	Outcome string    `json:"outcome"`
+	Error   string    `json:"error,omitempty"`
+}
+
+var lock sync.Mutex
+
+// Record appends the event to the audit file. The audit must not be lost silently, so a failure
+// to write it is returned, and also logged as an error.
+func Record(event Event) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	err = appendLine(data)
+	if err != nil {
+		slog.Error("Failed to record the audit event", "action", event.Action, "actor", event.Actor,
+			"error", err)
+	}
+	return err
+}
+
+func appendLine(data []byte) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("[E] Failed to create the audit directory: %w", err)
+	}
+	auditFile, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("[E] Failed to open the audit file: %w", err)
+	}
+	_, err = auditFile.Write(append(data, '\n'))
+	if closeErr := auditFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("[E] Failed to write the audit file: %w", err)
+	}
+	return nil
+}
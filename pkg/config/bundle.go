@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Bundle is a named group of skus that are assigned together, with the count of each sku.
+type Bundle map[string]int
+
+// Skus returns the names of the skus of the bundle, sorted.
+func (b Bundle) Skus() []string {
+	skus := make([]string, 0, len(b))
+	for sku := range b {
+		skus = append(skus, sku)
+	}
+	sort.Strings(skus)
+	return skus
+}
+
+// bundleFile is the content of a bundle file.
+type bundleFile struct {
+	Bundles map[string]Bundle `yaml:"bundles"`
+}
+
+// Bundles returns the bundles of the configuration file, and the ones of the bundle file if it
+// isn't empty. The bundles of the bundle file replace the ones with the same name.
+func (c *Config) Bundles(file string) (map[string]Bundle, error) {
+	bundles := make(map[string]Bundle)
+	for name, bundle := range c.BundleDefinitions {
+		bundles[name] = bundle
+	}
+	if file == "" {
+		return bundles, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("[E] Failed to read the bundle file: %w", err)
+	}
+	var content bundleFile
+	if err = yaml.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("[E] Failed to parse the bundle file '%s': %w", file, err)
+	}
+	for name, bundle := range content.Bundles {
+		bundles[name] = bundle
+	}
+	return bundles, nil
+}
+
+// Bundle returns the bundle with the given name.
+func (c *Config) Bundle(name string, file string) (Bundle, error) {
+	bundles, err := c.Bundles(file)
+	if err != nil {
+		return nil, err
+	}
+	bundle, existed := bundles[name]
+	if !existed {
+		names := make([]string, 0, len(bundles))
+		for name := range bundles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("[E] The bundle '%s' doesn't exist, the bundles are '%s'", name, strings.Join(names, "', '"))
+	}
+	if len(bundle) == 0 {
+		return nil, fmt.Errorf("[E] The bundle '%s' is empty", name)
+	}
+	for sku, count := range bundle {
+		if count < 0 {
+			return nil, fmt.Errorf("[E] The count of the sku '%s' of the bundle '%s' is negative", sku, name)
+		}
+	}
+	return bundle, nil
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+
+	"github/yasun1/myquota/pkg/policy"
+)
+
+const defaultProfile = "staging"
+
+var args struct {
+	file    string
+	profile string
+}
+
+// AddFlags adds the configuration flags to the given set of command line flags.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&args.file,
+		"config",
+		os.Getenv("MYQUOTA_CONFIG"),
+		"The configuration file. The default is the value of 'MYQUOTA_CONFIG', "+
+			"or 'myquota/config.yaml' in the user configuration directory.",
+	)
+	flags.StringVar(
+		&args.profile,
+		"profile",
+		"",
+		"The profile of the configuration file to use. The default is the value of 'OCM_ENV', or 'staging'.",
+	)
+}
+
+// Config is the content of the configuration file of the tool.
+type Config struct {
+	// Profiles are the settings of each OCM environment, indexed by the name of the environment.
+	Profiles map[string]*Profile `yaml:"profiles,omitempty"`
+	// APIKeys are the keys accepted by 'myquota serve', indexed by the name of their holder.
+	APIKeys map[string]*APIKey `yaml:"api_keys,omitempty"`
+	// BundleDefinitions are the groups of skus assigned together, indexed by the name of the bundle.
+	BundleDefinitions map[string]Bundle `yaml:"bundles,omitempty"`
+	// Groups are the organizations managed together, indexed by the name of the group.
+	Groups map[string]*Group `yaml:"groups,omitempty"`
+}
+
+// APIKey grants access to the API of 'myquota serve'. Only the SHA-256 digest of the key is
+// stored, so that the configuration file doesn't disclose it.
+type APIKey struct {
+	KeySHA256 string `yaml:"key_sha256"`
+	// Orgs are the ids of the organizations the key can read and change.
+	Orgs []string `yaml:"orgs,omitempty"`
+	// Skus are the skus the key can assign or remove, with the maximum count it can assign.
+	Skus map[string]int `yaml:"skus,omitempty"`
+	// Types are the types of the resource quotas the key can assign or remove, only 'Manual' when
+	// there are none.
+	Types []string `yaml:"types,omitempty"`
+	// AllowForce allows to remove resource quotas that are in use.
+	AllowForce bool `yaml:"allow_force,omitempty"`
+}
+
+// Profile contains the settings used to connect to an OCM environment.
+type Profile struct {
+	URL          string `yaml:"url,omitempty"`
+	TokenURL     string `yaml:"token_url,omitempty"`
+	ClientID     string `yaml:"client_id,omitempty"`
+	ClientSecret string `yaml:"client_secret,omitempty"`
+	AccessToken  string `yaml:"access_token,omitempty"`
+	RefreshToken string `yaml:"refresh_token,omitempty"`
+	Insecure     bool   `yaml:"insecure,omitempty"`
+	CAFile       string `yaml:"ca_file,omitempty"`
+	Proxy        string `yaml:"proxy,omitempty"`
+	// Policy limits the changes of the resource quota in the environment.
+	Policy *policy.Policy `yaml:"policy,omitempty"`
+}
+
+// HasCredentials returns whether the profile contains tokens or client credentials.
+func (p *Profile) HasCredentials() bool {
+	return p != nil && (p.AccessToken != "" || p.RefreshToken != "" || p.ClientSecret != "")
+}
+
+// Path returns the location of the configuration file.
+func Path() (string, error) {
+	if args.file != "" {
+		return args.file, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("[E] Failed to find the configuration directory: %w", err)
+	}
+	return filepath.Join(configDir, "myquota", "config.yaml"), nil
+}
+
+// ProfileName returns the name of the active profile.
+func ProfileName() string {
+	if args.profile != "" {
+		return args.profile
+	}
+	if env := os.Getenv("OCM_ENV"); env != "" {
+		return env
+	}
+	return defaultProfile
+}
+
+var (
+	loadOnce   sync.Once
+	loaded     *Config
+	loadedErr  error
+	loadedPath string
+)
+
+// Load reads the configuration file once, and returns the same configuration to every caller. A
+// missing file is the same as an empty configuration.
+func Load() (*Config, error) {
+	loadOnce.Do(func() {
+		loadedPath, loadedErr = Path()
+		if loadedErr != nil {
+			return
+		}
+		loaded, loadedErr = read(loadedPath)
+	})
+	return loaded, loadedErr
+}
+
+func read(path string) (*Config, error) {
+	config := &Config{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[E] Failed to read the configuration file: %w", err)
+	}
+	if err = yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("[E] Failed to parse the configuration file '%s': %w", path, err)
+	}
+	return config, nil
+}
+
+// Save writes the configuration back to the file it was loaded from. The file may contain
+// credentials, so only the owner can read it.
+func (c *Config) Save() error {
+	path := loadedPath
+	if path == "" {
+		var err error
+		if path, err = Path(); err != nil {
+			return err
+		}
+	}
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("[E] Failed to create the configuration directory: %w", err)
+	}
+	if err = os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("[E] Failed to write the configuration file: %w", err)
+	}
+	// The file may have been created with wider permissions before it contained credentials:
+	return os.Chmod(path, 0600)
+}
+
+// Profile returns the profile with the given name, creating it when it doesn't exist.
+func (c *Config) Profile(name string) *Profile {
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]*Profile)
+	}
+	if c.Profiles[name] == nil {
+		c.Profiles[name] = &Profile{}
+	}
+	return c.Profiles[name]
+}
+
+// ActiveProfile returns the active profile, or an empty profile if it isn't configured.
+func (c *Config) ActiveProfile() *Profile {
+	if profile := c.Profiles[ProfileName()]; profile != nil {
+		return profile
+	}
+	return &Profile{}
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Group is a named group of organizations, given by the usernames of their accounts or by their ids.
+type Group struct {
+	Usernames []string `yaml:"usernames,omitempty"`
+	OrgIDs    []string `yaml:"org_ids,omitempty"`
+}
+
+// Group returns the group with the given name.
+func (c *Config) Group(name string) (*Group, error) {
+	group, existed := c.Groups[name]
+	if !existed {
+		names := make([]string, 0, len(c.Groups))
+		for name := range c.Groups {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("[E] The group '%s' doesn't exist, the groups are '%s'", name, strings.Join(names, "', '"))
+	}
+	if group == nil || len(group.Usernames)+len(group.OrgIDs) == 0 {
+		return nil, fmt.Errorf("[E] The group '%s' is empty", name)
+	}
+	return group, nil
+}
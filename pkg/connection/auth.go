@@ -0,0 +1,370 @@
+package connection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	client "github.com/openshift-online/ocm-sdk-go"
+	"github.com/spf13/pflag"
+
+	"github/yasun1/myquota/pkg/config"
+)
+
+// Authentication methods
+const (
+	authAuto              = "auto"
+	authToken             = "token"
+	authTokenFile         = "token-file"
+	authClientCredentials = "client-credentials"
+	authOCMConfig         = "ocm-config"
+	authProfile           = "profile"
+)
+
+var authArgs struct {
+	method    string
+	tokenFile string
+	tokenURL  string
+	ocmConfig string
+}
+
+// AddFlags adds the authentication and transport flags to the given set of command line flags.
+func AddFlags(flags *pflag.FlagSet) {
+	addTransportFlags(flags)
+	flags.StringVar(
+		&authArgs.method,
+		"auth",
+		authAuto,
+		fmt.Sprintf("The authentication method, one of '%s', '%s', '%s', '%s', '%s' or '%s'. "+
+			"The 'auto' method uses the first available one of the client credentials in "+
+			"'OCM_CLIENT_ID' and 'OCM_CLIENT_SECRET', the token file, the token in "+
+			"'SUPER_ADMIN_USER_TOKEN', the credentials saved by 'myquota login' in the profile "+
+			"and the configuration of 'ocm login'.",
+			authAuto, authToken, authTokenFile, authClientCredentials, authProfile, authOCMConfig),
+	)
+	flags.StringVar(
+		&authArgs.tokenFile,
+		"token-file",
+		os.Getenv("OCM_TOKEN_FILE"),
+		"The file that contains the offline or access token. The default is the value of 'OCM_TOKEN_FILE'.",
+	)
+	flags.StringVar(
+		&authArgs.tokenURL,
+		"token-url",
+		os.Getenv("OCM_TOKEN_URL"),
+		"The URL of the token endpoint. The default is the value of 'OCM_TOKEN_URL', or the Red Hat SSO.",
+	)
+	flags.StringVar(
+		&authArgs.ocmConfig,
+		"ocm-config",
+		"",
+		"The configuration file written by 'ocm login'. The default is the value of 'OCM_CONFIG', "+
+			"or 'ocm/ocm.json' in the user configuration directory.",
+	)
+}
+
+// credentials are the parameters of the connection that identify the caller.
+type credentials struct {
+	method       string
+	tokens       []string
+	clientID     string
+	clientSecret string
+	tokenURL     string
+	url          string
+	scopes       []string
+}
+
+// ocmConfig is the part of the configuration file of the ocm command line tool that is reused.
+type ocmConfig struct {
+	AccessToken  string   `json:"access_token,omitempty"`
+	RefreshToken string   `json:"refresh_token,omitempty"`
+	ClientID     string   `json:"client_id,omitempty"`
+	ClientSecret string   `json:"client_secret,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+	TokenURL     string   `json:"token_url,omitempty"`
+	URL          string   `json:"url,omitempty"`
+}
+
+// resolveCredentials returns the credentials of the selected authentication method.
+func resolveCredentials() (*credentials, error) {
+	creds, err := selectCredentials()
+	if err != nil {
+		return nil, err
+	}
+	return applyDefaults(creds)
+}
+
+// selectCredentials returns the credentials of the selected authentication method, as they are
+// found in their source.
+func selectCredentials() (*credentials, error) {
+	var creds *credentials
+	var err error
+	switch authArgs.method {
+	case authToken:
+		creds, err = tokenCredentials()
+	case authTokenFile:
+		creds, err = tokenFileCredentials()
+	case authClientCredentials:
+		creds, err = clientCredentials()
+	case authOCMConfig:
+		creds, err = ocmConfigCredentials()
+	case authProfile:
+		creds, err = profileCredentials()
+	case authAuto, "":
+		creds, err = autoCredentials()
+	default:
+		err = fmt.Errorf("[E] The authentication method '%s' is invalid", authArgs.method)
+	}
+	return creds, err
+}
+
+// applyDefaults completes the credentials with the settings of the profile, the overrides of the
+// command line and the default values.
+func applyDefaults(creds *credentials) (*credentials, error) {
+	// The settings of the profile apply to every authentication method:
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	profile := cfg.ActiveProfile()
+	if profile.URL != "" {
+		creds.url = profile.URL
+	}
+	if creds.tokenURL == "" {
+		creds.tokenURL = profile.TokenURL
+	}
+
+	if creds.clientID == "" {
+		creds.clientID = clientID
+	}
+	if authArgs.tokenURL != "" {
+		creds.tokenURL = authArgs.tokenURL
+	}
+	if creds.tokenURL == "" {
+		creds.tokenURL = tokenURL
+	}
+	return creds, nil
+}
+
+// autoCredentials picks the first authentication method that is configured.
+func autoCredentials() (*credentials, error) {
+	switch {
+	case os.Getenv("OCM_CLIENT_ID") != "" || os.Getenv("OCM_CLIENT_SECRET") != "":
+		return clientCredentials()
+	case authArgs.tokenFile != "":
+		return tokenFileCredentials()
+	case os.Getenv("SUPER_ADMIN_USER_TOKEN") != "":
+		return tokenCredentials()
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ActiveProfile().HasCredentials() {
+		return profileCredentials()
+	}
+	if _, err := os.Stat(ocmConfigPath()); err == nil {
+		return ocmConfigCredentials()
+	}
+	return nil, fmt.Errorf("[E] No credentials found. Set 'SUPER_ADMIN_USER_TOKEN', " +
+		"'OCM_CLIENT_ID' and 'OCM_CLIENT_SECRET', the option '--token-file', or run 'myquota login' or 'ocm login'")
+}
+
+func tokenCredentials() (*credentials, error) {
+	token := os.Getenv("SUPER_ADMIN_USER_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("[E] The variable 'SUPER_ADMIN_USER_TOKEN' is empty")
+	}
+	return &credentials{
+		method: authToken,
+		tokens: []string{token},
+	}, nil
+}
+
+func tokenFileCredentials() (*credentials, error) {
+	if authArgs.tokenFile == "" {
+		return nil, fmt.Errorf("[E] The option '--token-file' is mandatory for the '%s' authentication", authTokenFile)
+	}
+	data, err := os.ReadFile(authArgs.tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("[E] Failed to read the token file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return nil, fmt.Errorf("[E] The token file '%s' is empty", authArgs.tokenFile)
+	}
+	return &credentials{
+		method: authTokenFile,
+		tokens: []string{token},
+	}, nil
+}
+
+func clientCredentials() (*credentials, error) {
+	id := os.Getenv("OCM_CLIENT_ID")
+	secret := os.Getenv("OCM_CLIENT_SECRET")
+	if id == "" || secret == "" {
+		return nil, fmt.Errorf("[E] Both 'OCM_CLIENT_ID' and 'OCM_CLIENT_SECRET' are required for the '%s' authentication",
+			authClientCredentials)
+	}
+	return &credentials{
+		method:       authClientCredentials,
+		clientID:     id,
+		clientSecret: secret,
+	}, nil
+}
+
+// ocmConfigPath returns the location of the configuration file of the ocm command line tool,
+// following the same rules as the tool itself.
+func ocmConfigPath() string {
+	if authArgs.ocmConfig != "" {
+		return authArgs.ocmConfig
+	}
+	if path := os.Getenv("OCM_CONFIG"); path != "" {
+		return path
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "ocm", "ocm.json")
+}
+
+func ocmConfigCredentials() (*credentials, error) {
+	path := ocmConfigPath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("[E] Failed to read the ocm configuration, please run 'ocm login': %w", err)
+	}
+	var ocm ocmConfig
+	if err = json.Unmarshal(data, &ocm); err != nil {
+		return nil, fmt.Errorf("[E] Failed to parse the ocm configuration '%s': %w", path, err)
+	}
+
+	var tokens []string
+	if ocm.AccessToken != "" {
+		tokens = append(tokens, ocm.AccessToken)
+	}
+	if ocm.RefreshToken != "" {
+		tokens = append(tokens, ocm.RefreshToken)
+	}
+	if len(tokens) == 0 && ocm.ClientSecret == "" {
+		return nil, fmt.Errorf("[E] The ocm configuration '%s' contains no tokens, please run 'ocm login'", path)
+	}
+	creds := &credentials{
+		method:       authOCMConfig,
+		tokens:       tokens,
+		clientID:     ocm.ClientID,
+		clientSecret: ocm.ClientSecret,
+		tokenURL:     ocm.TokenURL,
+		scopes:       ocm.Scopes,
+	}
+	// The ocm configuration remembers the environment it was logged in to:
+	if os.Getenv("OCM_ENV") == "" {
+		creds.url = ocm.URL
+	}
+	return creds, nil
+}
+
+func profileCredentials() (*credentials, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	profile := cfg.ActiveProfile()
+	if !profile.HasCredentials() {
+		return nil, fmt.Errorf("[E] The profile '%s' contains no credentials, please run 'myquota login'",
+			config.ProfileName())
+	}
+	return credentialsFromProfile(profile), nil
+}
+
+func credentialsFromProfile(profile *config.Profile) *credentials {
+	var tokens []string
+	if profile.AccessToken != "" {
+		tokens = append(tokens, profile.AccessToken)
+	}
+	if profile.RefreshToken != "" {
+		tokens = append(tokens, profile.RefreshToken)
+	}
+	return &credentials{
+		method:       authProfile,
+		tokens:       tokens,
+		clientID:     profile.ClientID,
+		clientSecret: profile.ClientSecret,
+		tokenURL:     profile.TokenURL,
+		url:          profile.URL,
+	}
+}
+
+// Login creates a connection with the given token or client credentials, or when they are empty
+// with the credentials selected by the '--auth' option. It requests the tokens to check that the
+// credentials are valid, and returns the profile that stores them.
+func Login(ctx context.Context, token string, id string, secret string) (*config.Profile, *client.Connection, error) {
+	var creds *credentials
+	var err error
+	switch {
+	case token != "":
+		creds = &credentials{
+			method: authToken,
+			tokens: []string{token},
+		}
+	case id != "" || secret != "":
+		if id == "" || secret == "" {
+			return nil, nil, fmt.Errorf("[E] Both the client ID and the client secret are required")
+		}
+		creds = &credentials{
+			method:       authClientCredentials,
+			clientID:     id,
+			clientSecret: secret,
+		}
+	default:
+		creds, err = selectCredentials()
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if creds, err = applyDefaults(creds); err != nil {
+		return nil, nil, err
+	}
+
+	connection, err := createConnection(creds)
+	if err != nil {
+		return nil, nil, err
+	}
+	access, refresh, err := connection.TokensContext(ctx)
+	if err != nil {
+		connection.Close()
+		return nil, nil, fmt.Errorf("[E] Failed to get the tokens: %w", err)
+	}
+
+	transport, err := resolveTransport(creds)
+	if err != nil {
+		connection.Close()
+		return nil, nil, err
+	}
+	profile := &config.Profile{
+		URL:      connection.URL(),
+		TokenURL: connection.TokenURL(),
+		Insecure: transport.insecure,
+		CAFile:   transport.caFile,
+	}
+	if transport.proxy != nil {
+		profile.Proxy = transport.proxy.String()
+	}
+	if creds.method == authClientCredentials || creds.clientSecret != "" {
+		profile.ClientID = creds.clientID
+		profile.ClientSecret = creds.clientSecret
+	} else if creds.clientID != clientID {
+		profile.ClientID = creds.clientID
+	}
+	// The refresh token outlives the access token, so only one of them is kept:
+	if refresh != "" {
+		profile.RefreshToken = refresh
+	} else if profile.ClientSecret == "" {
+		profile.AccessToken = access
+	}
+	return profile, connection, nil
+}
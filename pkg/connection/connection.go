@@ -18,88 +18,265 @@ limitations under the License.
 
 import (
 	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"sync"
 
-	. "github.com/onsi/ginkgo"
 	client "github.com/openshift-online/ocm-sdk-go"
+	"github.com/spf13/pflag"
+
+	"github/yasun1/myquota/pkg/config"
 )
 
 const (
 	tokenURL       = "https://sso.redhat.com/auth/realms/redhat-external/protocol/openid-connect/token"
 	clientID       = "cloud-services"
 	clientSecret   = ""
-	skipAuth       = true
 	integration    = false
 	healthcheckURL = "http://localhost:8083"
 )
 
-func gatewayURL() (url string) {
-	ocmEnv := os.Getenv("OCM_ENV")
-	switch ocmEnv {
-	case "production":
-		url = "https://api.openshift.com"
-	case "staging":
-		url = "https://api.stage.openshift.com"
-	case "integration":
-		url = "https://api.integration.openshift.com"
-	default:
-		url = "https://api.stage.openshift.com"
+var transportArgs struct {
+	url      string
+	insecure bool
+	caFile   string
+	proxy    string
+}
+
+// addTransportFlags adds the flags that control where and how the tool connects.
+func addTransportFlags(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&transportArgs.url,
+		"url",
+		os.Getenv("OCM_URL"),
+		"The URL of the API gateway. The default is the value of 'OCM_URL', the URL of the profile, "+
+			"or the URL of the environment selected by 'OCM_ENV'.",
+	)
+	flags.BoolVar(
+		&transportArgs.insecure,
+		"insecure",
+		false,
+		"If the insecure is true, will skip the verification of the TLS certificates of the servers.",
+	)
+	flags.StringVar(
+		&transportArgs.caFile,
+		"ca-file",
+		"",
+		"The file that contains the certificates of additional trusted certificate authorities, in PEM format.",
+	)
+	flags.StringVar(
+		&transportArgs.proxy,
+		"proxy",
+		"",
+		"The URL of the HTTP(S) proxy. The default is the value of 'HTTPS_PROXY' or 'HTTP_PROXY'.",
+	)
+}
+
+// transport are the settings of the connection that aren't related to the credentials.
+type transport struct {
+	url      string
+	insecure bool
+	caFile   string
+	proxy    *url.URL
+}
+
+// resolveTransport merges the command line flags with the settings of the profile.
+func resolveTransport(creds *credentials) (*transport, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
 	}
+	profile := cfg.ActiveProfile()
 
-	return url
+	result := &transport{
+		url:      gatewayURL(),
+		insecure: transportArgs.insecure || profile.Insecure,
+		caFile:   profile.CAFile,
+	}
+	if creds.url != "" {
+		result.url = creds.url
+	}
+	if transportArgs.url != "" {
+		result.url = transportArgs.url
+	}
+	if transportArgs.caFile != "" {
+		result.caFile = transportArgs.caFile
+	}
+
+	proxy := profile.Proxy
+	if transportArgs.proxy != "" {
+		proxy = transportArgs.proxy
+	}
+	if proxy != "" {
+		result.proxy, err = url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("[E] The proxy URL '%s' is invalid: %w", proxy, err)
+		}
+	}
+	return result, nil
 }
 
-// SuperAdmin
-var (
-	superAdminUserToken  = os.Getenv("SUPER_ADMIN_USER_TOKEN")
-	SuperAdminConnection = createConnectionWithToken(superAdminUserToken)
-)
+// proxyWrapper returns a transport wrapper that sends the requests through the given proxy. It
+// must be the last wrapper of the connection, so that it receives the transport created by the SDK.
+func proxyWrapper(proxy *url.URL) client.TransportWrapper {
+	return func(wrapped http.RoundTripper) http.RoundTripper {
+		transport, ok := wrapped.(*http.Transport)
+		if !ok {
+			slog.Warn("Can't set the proxy of the transport", "type", fmt.Sprintf("%T", wrapped))
+			return wrapped
+		}
+		transport = transport.Clone()
+		transport.Proxy = http.ProxyURL(proxy)
+		return transport
+	}
+}
 
-var (
-	// Create a logger:
-	logger = createLogger()
-)
+// environmentURLs are the URLs of the API gateways of the known OCM environments.
+var environmentURLs = map[string]string{
+	"production":  "https://api.openshift.com",
+	"staging":     "https://api.stage.openshift.com",
+	"integration": "https://api.integration.openshift.com",
+}
 
-func createConnectionWithToken(token string) *client.Connection {
-	gatewayURL := gatewayURL()
+// EnvironmentURL returns the URL of the API gateway of the named OCM environment, or an empty
+// string if the environment isn't known.
+func EnvironmentURL(name string) string {
+	return environmentURLs[name]
+}
 
-	if token == "" {
-		fmt.Println("[WARNING]: Token shouldn't be empty")
+func gatewayURL() string {
+	if url := EnvironmentURL(os.Getenv("OCM_ENV")); url != "" {
+		return url
 	}
+	return environmentURLs["staging"]
+}
 
-	// Create the connection:
-	connection, err := client.NewConnectionBuilder().
-		Logger(logger).
-		Insecure(true).
-		TokenURL(tokenURL).
-		URL(gatewayURL).
-		Client(clientID, clientSecret).
-		Tokens(token).
-		Build()
+// ProfileURL returns the URL of the API gateway the named profile is for: its 'url', or the one
+// of the environment of its name, or the one of the default environment.
+func ProfileURL(name string, profile *config.Profile) string {
+	if profile != nil && profile.URL != "" {
+		return strings.TrimSuffix(profile.URL, "/")
+	}
+	if url := EnvironmentURL(name); url != "" {
+		return url
+	}
+	return environmentURLs["staging"]
+}
+
+// GatewayURL returns the URL of the API gateway that the commands connect to, resolved from the
+// options, the credentials and the profile like the connection does, without connecting.
+func GatewayURL() (string, error) {
+	creds, err := resolveCredentials()
+	if err != nil {
+		return "", err
+	}
+	transport, err := resolveTransport(creds)
 	if err != nil {
-		fmt.Printf("ERROR occurred when create connection with token: %s!! %s\n", token, err)
+		return "", err
 	}
-	return connection
+	return strings.TrimSuffix(transport.url, "/"), nil
+}
 
+// Environment returns the name of the OCM environment that the commands connect to, or the URL of
+// its API gateway if it isn't a known environment.
+func Environment() (string, error) {
+	url, err := GatewayURL()
+	if err != nil {
+		return "", err
+	}
+	for name, environmentURL := range environmentURLs {
+		if url == environmentURL {
+			return name, nil
+		}
+	}
+	return url, nil
 }
 
-func createLogger() client.Logger {
-	debugMode := false
-	if os.Getenv("OCM_Debug_Mode") == "true" {
-		debugMode = true
+// ProfileEnvironment returns the environment of the manifests, which is the one the commands connect
+// to, and refuses the active profile if it is for another gateway, etc, the default profile with
+// '--url https://api.openshift.com'. A gateway of no known environment is named after the profile.
+func ProfileEnvironment() (string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return "", err
+	}
+	gateway, err := GatewayURL()
+	if err != nil {
+		return "", err
+	}
+	name := config.ProfileName()
+	if profileURL := ProfileURL(name, cfg.Profiles[name]); profileURL != gateway {
+		return "", fmt.Errorf("[E] The profile '%s' is for the gateway '%s', but the commands connect to '%s'.",
+			name, profileURL, gateway)
+	}
+	environment, err := Environment()
+	if err != nil {
+		return "", err
+	}
+	if EnvironmentURL(environment) == "" {
+		return name, nil
 	}
-	logger, _ := client.NewStdLoggerBuilder().
-		Streams(GinkgoWriter, GinkgoWriter).
-		Debug(debugMode).
-		Build()
-	// ExpectWithOffset(1, err).ToNot(HaveOccurred())
-	// logger, _ := client.NewGlogLoggerBuilder().DebugV(3).InfoV(0).Build()
+	return environment, nil
+}
 
-	return logger
+// SuperAdmin
+var (
+	superAdminOnce       sync.Once
+	superAdminConnection *client.Connection
+	superAdminErr        error
+)
+
+// SuperAdminConnection returns the connection authenticated with the credentials selected by the
+// '--auth' option, by default the super admin token. The connection is created on first use, so
+// that the command line flags are already parsed.
+func SuperAdminConnection() (*client.Connection, error) {
+	superAdminOnce.Do(func() {
+		var creds *credentials
+		creds, superAdminErr = resolveCredentials()
+		if superAdminErr != nil {
+			return
+		}
+		superAdminConnection, superAdminErr = createConnection(creds)
+	})
+	return superAdminConnection, superAdminErr
 }
 
-func checkSkipAuth() {
-	if skipAuth {
-		Skip("Test skipped due to authorization issue")
+func createConnection(creds *credentials) (*client.Connection, error) {
+	transport, err := resolveTransport(creds)
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("Creating the connection", "url", transport.url, "token_url", creds.tokenURL,
+		"auth", creds.method, "client_id", creds.clientID, "insecure", transport.insecure,
+		"ca_file", transport.caFile, "proxy", transport.proxy)
+	if transport.insecure {
+		slog.Warn("The TLS certificates of the servers are not verified")
+	}
+
+	// Create the connection:
+	builder := client.NewConnectionBuilder().
+		Logger(&sdkLogger{}).
+		TransportWrapper(wrapWithTrace).
+		Insecure(transport.insecure).
+		TokenURL(creds.tokenURL).
+		URL(transport.url).
+		Client(creds.clientID, creds.clientSecret).
+		Tokens(creds.tokens...)
+	if len(creds.scopes) != 0 {
+		builder = builder.Scopes(creds.scopes...)
+	}
+	if transport.caFile != "" {
+		builder = builder.TrustedCAFile(transport.caFile)
+	}
+	if transport.proxy != nil {
+		builder = builder.TransportWrapper(proxyWrapper(transport.proxy))
+	}
+	connection, err := builder.Build()
+	if err != nil {
+		return nil, fmt.Errorf("[E] Failed to create the connection to %s: %w", transport.url, err)
 	}
+	return connection, nil
 }
@@ -0,0 +1,61 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// sdkLogger sends the messages of the SDK to the default structured logger.
+type sdkLogger struct{}
+
+func (l *sdkLogger) enabled(level slog.Level) bool {
+	return slog.Default().Enabled(context.Background(), level)
+}
+
+func (l *sdkLogger) log(ctx context.Context, level slog.Level, format string, args ...interface{}) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	slog.Default().Log(ctx, level, fmt.Sprintf(format, args...), "component", "sdk")
+}
+
+// DebugEnabled returns false, so that the SDK doesn't dump the HTTP traffic line by line. The
+// traffic is traced by the tracing transport instead, see trace.go.
+func (l *sdkLogger) DebugEnabled() bool {
+	return false
+}
+
+func (l *sdkLogger) InfoEnabled() bool {
+	return l.enabled(slog.LevelInfo)
+}
+
+func (l *sdkLogger) WarnEnabled() bool {
+	return l.enabled(slog.LevelWarn)
+}
+
+func (l *sdkLogger) ErrorEnabled() bool {
+	return l.enabled(slog.LevelError)
+}
+
+func (l *sdkLogger) Debug(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, slog.LevelDebug, format, args...)
+}
+
+func (l *sdkLogger) Info(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, slog.LevelInfo, format, args...)
+}
+
+func (l *sdkLogger) Warn(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, slog.LevelWarn, format, args...)
+}
+
+func (l *sdkLogger) Error(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, slog.LevelError, format, args...)
+}
+
+func (l *sdkLogger) Fatal(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, slog.LevelError, format, args...)
+	os.Exit(1)
+}
@@ -0,0 +1,163 @@
+package connection
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const redacted = "***"
+
+// sensitiveFields are the JSON attributes, form fields and headers whose values are never logged.
+var sensitiveFields = map[string]bool{
+	"access_token":  true,
+	"refresh_token": true,
+	"id_token":      true,
+	"client_secret": true,
+	"password":      true,
+	"authorization": true,
+	"cookie":        true,
+	"set-cookie":    true,
+}
+
+// traceTransport logs every HTTP request and response in debug level, with the tokens redacted.
+type traceTransport struct {
+	wrapped http.RoundTripper
+}
+
+func wrapWithTrace(wrapped http.RoundTripper) http.RoundTripper {
+	return &traceTransport{
+		wrapped: wrapped,
+	}
+}
+
+func (t *traceTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	ctx := request.Context()
+	if !slog.Default().Enabled(ctx, slog.LevelDebug) {
+		return t.wrapped.RoundTrip(request)
+	}
+
+	var requestBody []byte
+	if request.Body != nil {
+		var err error
+		requestBody, err = io.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		request.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+	slog.DebugContext(ctx, "HTTP request",
+		"method", request.Method,
+		"url", redactURL(request.URL),
+		"header", redactHeader(request.Header),
+		"body", redactBody(request.Header, requestBody),
+	)
+
+	start := time.Now()
+	response, err := t.wrapped.RoundTrip(request)
+	if err != nil {
+		slog.DebugContext(ctx, "HTTP request failed",
+			"method", request.Method,
+			"url", redactURL(request.URL),
+			"duration", time.Since(start),
+			"error", err,
+		)
+		return nil, err
+	}
+
+	responseBody, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = io.NopCloser(bytes.NewReader(responseBody))
+	slog.DebugContext(ctx, "HTTP response",
+		"method", request.Method,
+		"url", redactURL(request.URL),
+		"status", response.StatusCode,
+		"duration", time.Since(start),
+		"header", redactHeader(response.Header),
+		"body", redactBody(response.Header, responseBody),
+	)
+	return response, nil
+}
+
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	for name := range query {
+		if sensitiveFields[strings.ToLower(name)] {
+			query.Set(name, redacted)
+		}
+	}
+	clone := *u
+	clone.RawQuery = query.Encode()
+	return clone.String()
+}
+
+func redactHeader(header http.Header) map[string]string {
+	result := make(map[string]string, len(header))
+	for name := range header {
+		value := header.Get(name)
+		if sensitiveFields[strings.ToLower(name)] {
+			value = redacted
+		}
+		result[name] = value
+	}
+	return result
+}
+
+func redactBody(header http.Header, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return redacted
+		}
+		for name := range values {
+			if sensitiveFields[strings.ToLower(name)] {
+				values.Set(name, redacted)
+			}
+		}
+		return values.Encode()
+	default:
+		var object interface{}
+		if err := json.Unmarshal(body, &object); err != nil {
+			// Don't risk logging a token that isn't in a known format:
+			return redacted
+		}
+		result, err := json.Marshal(redactJSON(object))
+		if err != nil {
+			return redacted
+		}
+		return string(result)
+	}
+}
+
+func redactJSON(object interface{}) interface{} {
+	switch value := object.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			if sensitiveFields[strings.ToLower(key)] {
+				value[key] = redacted
+			} else {
+				value[key] = redactJSON(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redactJSON(item)
+		}
+	}
+	return object
+}
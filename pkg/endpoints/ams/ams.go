@@ -1,6 +1,7 @@
 package ams
 
 import (
+	"context"
 	"fmt"
 
 	client "github.com/openshift-online/ocm-sdk-go"
@@ -26,7 +27,7 @@ func parameters(request *client.Request, parameters ...map[string]interface{}) *
 }
 
 // Account
-func ListAccounts(connection *client.Connection, params ...map[string]interface{}) (resp *client.Response, err error) {
+func ListAccounts(ctx context.Context, connection *client.Connection, params ...map[string]interface{}) (resp *client.Response, err error) {
 	if len(params) > 1 {
 		err = parameterError(len(params))
 		return
@@ -34,12 +35,34 @@ func ListAccounts(connection *client.Connection, params ...map[string]interface{
 
 	request := connection.Get().Path(accountURL)
 	request = parameters(request, params...)
-	return request.Send()
+	return request.SendContext(ctx)
+}
+
+func RetrieveCurrentAccount(ctx context.Context, connection *client.Connection) (resp *client.Response, err error) {
+	resp, err = connection.Get().Path(currentAccountURL).SendContext(ctx)
+	return
+}
+
+func ListRoleBindings(ctx context.Context, connection *client.Connection, params ...map[string]interface{}) (resp *client.Response, err error) {
+	if len(params) > 1 {
+		err = parameterError(len(params))
+		return
+	}
+
+	request := connection.Get().Path(roleBindingURL)
+	request = parameters(request, params...)
+	return request.SendContext(ctx)
+}
+
+// Authorizations
+func CreateSelfAccessReview(ctx context.Context, connection *client.Connection, body string) (resp *client.Response, err error) {
+	resp, err = connection.Post().Path(selfAccessReviewURL).String(body).SendContext(ctx)
+	return
 }
 
 // Quota
 
-func ListSkuRules(connection *client.Connection, params ...map[string]interface{}) (resp *client.Response, err error) {
+func ListSkuRules(ctx context.Context, connection *client.Connection, params ...map[string]interface{}) (resp *client.Response, err error) {
 	if len(params) > 1 {
 		err = parameterError(len(params))
 		return nil, err
@@ -50,15 +73,15 @@ func ListSkuRules(connection *client.Connection, params ...map[string]interface{
 			request = request.Parameter(key, value)
 		}
 	}
-	return request.Send()
+	return request.SendContext(ctx)
 }
 
-func RetrieveSkuRuleByID(connection *client.Connection, skuRuleID string) (resp *client.Response, err error) {
-	resp, err = connection.Get().Path(fmt.Sprintf(skuRuleIDURL, skuRuleID)).Send()
+func RetrieveSkuRuleByID(ctx context.Context, connection *client.Connection, skuRuleID string) (resp *client.Response, err error) {
+	resp, err = connection.Get().Path(fmt.Sprintf(skuRuleIDURL, skuRuleID)).SendContext(ctx)
 	return
 }
 
-func RetrieveQuotaCost(connection *client.Connection, organizationID string, params ...map[string]interface{}) (resp *client.Response, err error) {
+func RetrieveQuotaCost(ctx context.Context, connection *client.Connection, organizationID string, params ...map[string]interface{}) (resp *client.Response, err error) {
 	if len(params) > 1 {
 		err = parameterError(len(params))
 		return nil, err
@@ -66,10 +89,10 @@ func RetrieveQuotaCost(connection *client.Connection, organizationID string, par
 
 	request := connection.Get().Path(fmt.Sprintf(quotaCostURL, organizationID))
 	request = parameters(request, params...)
-	return request.Send()
+	return request.SendContext(ctx)
 }
 
-func ListOrgResourceQuotas(connection *client.Connection, organizationID string, params ...map[string]interface{}) (resp *client.Response, err error) {
+func ListOrgResourceQuotas(ctx context.Context, connection *client.Connection, organizationID string, params ...map[string]interface{}) (resp *client.Response, err error) {
 	if len(params) > 1 {
 		err = parameterError(len(params))
 		return
@@ -77,25 +100,25 @@ func ListOrgResourceQuotas(connection *client.Connection, organizationID string,
 
 	request := connection.Get().Path(fmt.Sprintf(resourceQuotaURL, organizationID))
 	request = parameters(request, params...)
-	return request.Send()
+	return request.SendContext(ctx)
 }
 
-func CreateOrgResourceQuota(connection *client.Connection, organizationID string, body string) (resp *client.Response, err error) {
-	resp, err = connection.Post().Path(fmt.Sprintf(resourceQuotaURL, organizationID)).String(body).Send()
+func CreateOrgResourceQuota(ctx context.Context, connection *client.Connection, organizationID string, body string) (resp *client.Response, err error) {
+	resp, err = connection.Post().Path(fmt.Sprintf(resourceQuotaURL, organizationID)).String(body).SendContext(ctx)
 	return
 }
 
-func RetrieveOrgResourceQuotaByID(connection *client.Connection, organizationID string, quotaID string) (resp *client.Response, err error) {
-	resp, err = connection.Get().Path(fmt.Sprintf(resourceQuotaIDURL, organizationID, quotaID)).Send()
+func RetrieveOrgResourceQuotaByID(ctx context.Context, connection *client.Connection, organizationID string, quotaID string) (resp *client.Response, err error) {
+	resp, err = connection.Get().Path(fmt.Sprintf(resourceQuotaIDURL, organizationID, quotaID)).SendContext(ctx)
 	return
 }
 
-func PatchOrgResourceQuotaByID(connection *client.Connection, organizationID string, quotaID string, body string) (resp *client.Response, err error) {
-	resp, err = connection.Patch().Path(fmt.Sprintf(resourceQuotaIDURL, organizationID, quotaID)).String(body).Send()
+func PatchOrgResourceQuotaByID(ctx context.Context, connection *client.Connection, organizationID string, quotaID string, body string) (resp *client.Response, err error) {
+	resp, err = connection.Patch().Path(fmt.Sprintf(resourceQuotaIDURL, organizationID, quotaID)).String(body).SendContext(ctx)
 	return
 }
 
-func DeleteOrgResourceQuotaByID(connection *client.Connection, organizationID string, quotaID string) (resp *client.Response, err error) {
-	resp, err = connection.Delete().Path(fmt.Sprintf(resourceQuotaIDURL, organizationID, quotaID)).Send()
+func DeleteOrgResourceQuotaByID(ctx context.Context, connection *client.Connection, organizationID string, quotaID string) (resp *client.Response, err error) {
+	resp, err = connection.Delete().Path(fmt.Sprintf(resourceQuotaIDURL, organizationID, quotaID)).SendContext(ctx)
 	return
 }
@@ -3,7 +3,9 @@ package ams
 // Account Management Service
 const (
 	// account
-	accountURL = "/api/accounts_mgmt/v1/accounts"
+	accountURL        = "/api/accounts_mgmt/v1/accounts"
+	currentAccountURL = "/api/accounts_mgmt/v1/current_account"
+	roleBindingURL    = "/api/accounts_mgmt/v1/role_bindings"
 
 	// quota
 	skuRuleURL         = "/api/accounts_mgmt/v1/sku_rules"
@@ -12,3 +14,8 @@ const (
 	resourceQuotaURL   = "/api/accounts_mgmt/v1/organizations/%s/resource_quota"
 	resourceQuotaIDURL = "/api/accounts_mgmt/v1/organizations/%s/resource_quota/%s"
 )
+
+// Authorizations
+const (
+	selfAccessReviewURL = "/api/authorizations/v1/self_access_review"
+)
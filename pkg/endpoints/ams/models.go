@@ -0,0 +1,136 @@
+package ams
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Account is the part of the AMS account used by the tool.
+type Account struct {
+	ID           string        `json:"id"`
+	Username     string        `json:"username"`
+	Email        string        `json:"email,omitempty"`
+	Organization *Organization `json:"organization,omitempty"`
+}
+
+// Organization is the part of the AMS organization used by the tool.
+type Organization struct {
+	ID         string `json:"id"`
+	Name       string `json:"name,omitempty"`
+	ExternalID string `json:"external_id,omitempty"`
+}
+
+// SkuRule maps a sku to the quota it consumes.
+type SkuRule struct {
+	ID      string `json:"id"`
+	Sku     string `json:"sku"`
+	QuotaID string `json:"quota_id"`
+}
+
+// QuotaCost is the usage of a quota in an organization.
+type QuotaCost struct {
+	QuotaID  string `json:"quota_id"`
+	Allowed  int    `json:"allowed"`
+	Consumed int    `json:"consumed"`
+}
+
+// ResourceQuota is a quota assigned to an organization. It is also used as the body of the
+// requests that create or update a resource quota, in that case the ID is left empty.
+type ResourceQuota struct {
+	ID       string `json:"id,omitempty"`
+	Sku      string `json:"sku"`
+	SkuCount int    `json:"sku_count"`
+	Type     string `json:"type"`
+}
+
+// RoleBinding binds a role to an account.
+type RoleBinding struct {
+	ID   string     `json:"id"`
+	Type string     `json:"type"`
+	Role *ObjectRef `json:"role,omitempty"`
+}
+
+// ObjectRef is a reference to another object.
+type ObjectRef struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind,omitempty"`
+}
+
+// SelfAccessReview asks whether the caller is allowed to perform an action on a type of resource.
+// The field 'allowed' is only set in the response.
+type SelfAccessReview struct {
+	Action         string `json:"action"`
+	ResourceType   string `json:"resource_type"`
+	OrganizationID string `json:"organization_id,omitempty"`
+	Allowed        bool   `json:"allowed,omitempty"`
+}
+
+// required checks that the JSON object contains all the given attributes and that none of
+// them is null, so that a missing attribute is reported instead of silently becoming a zero value.
+func required(data []byte, kind string, names ...string) error {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return fmt.Errorf("can't parse the %s: %w", kind, err)
+	}
+	for _, name := range names {
+		if value, existed := object[name]; !existed || string(value) == "null" {
+			return fmt.Errorf("the attribute '%s' of the %s is missing", name, kind)
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *Account) UnmarshalJSON(data []byte) error {
+	if err := required(data, "account", "id"); err != nil {
+		return err
+	}
+	type plain Account
+	return json.Unmarshal(data, (*plain)(a))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *SkuRule) UnmarshalJSON(data []byte) error {
+	if err := required(data, "sku rule", "sku", "quota_id"); err != nil {
+		return err
+	}
+	type plain SkuRule
+	return json.Unmarshal(data, (*plain)(r))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *QuotaCost) UnmarshalJSON(data []byte) error {
+	if err := required(data, "quota cost", "quota_id", "allowed", "consumed"); err != nil {
+		return err
+	}
+	type plain QuotaCost
+	return json.Unmarshal(data, (*plain)(c))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (q *ResourceQuota) UnmarshalJSON(data []byte) error {
+	if err := required(data, "resource quota", "id", "sku", "sku_count", "type"); err != nil {
+		return err
+	}
+	type plain ResourceQuota
+	return json.Unmarshal(data, (*plain)(q))
+}
+
+// Unmarshal parses the body of a response that contains a single object.
+func Unmarshal[T any](data []byte) (T, error) {
+	var object T
+	err := json.Unmarshal(data, &object)
+	return object, err
+}
+
+// UnmarshalList parses the body of a response that contains a list, and returns its items.
+func UnmarshalList[T any](data []byte) ([]T, error) {
+	if err := required(data, "list", "items"); err != nil {
+		return nil, err
+	}
+	var list struct {
+		Items []T `json:"items"`
+	}
+	err := json.Unmarshal(data, &list)
+	return list.Items, err
+}
@@ -0,0 +1,122 @@
+package exporter
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github/yasun1/myquota/pkg/quota"
+)
+
+const namespace = "myquota"
+
+// Exporter polls the quota cost of the organizations of the given users, and exposes it as
+// Prometheus metrics.
+type Exporter struct {
+	usernames []string
+	interval  time.Duration
+	registry  *prometheus.Registry
+
+	allowed      *prometheus.GaugeVec
+	consumed     *prometheus.GaugeVec
+	scrapes      *prometheus.CounterVec
+	scrapeErrors *prometheus.CounterVec
+	lastScrape   *prometheus.GaugeVec
+}
+
+// New creates an exporter that polls the organizations of the given users every interval.
+func New(usernames []string, interval time.Duration) *Exporter {
+	labels := []string{"org", "quota_id", "sku"}
+	e := &Exporter{
+		usernames: usernames,
+		interval:  interval,
+		registry:  prometheus.NewRegistry(),
+		allowed: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "allowed",
+			Help:      "Number of the resources that the organization is allowed to use.",
+		}, labels),
+		consumed: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "consumed",
+			Help:      "Number of the resources that the organization is using.",
+		}, labels),
+		scrapes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "scrapes_total",
+			Help:      "Number of the polls of the quota cost of the organization of the user.",
+		}, []string{"user"}),
+		scrapeErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "scrape_errors_total",
+			Help:      "Number of the failed polls of the quota cost of the organization of the user.",
+		}, []string{"user"}),
+		lastScrape: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "last_scrape_success_timestamp_seconds",
+			Help:      "Time of the last successful poll of the quota cost of the organization of the user.",
+		}, []string{"user", "org"}),
+	}
+	e.registry.MustRegister(e.allowed, e.consumed, e.scrapes, e.scrapeErrors, e.lastScrape)
+	return e
+}
+
+// Handler returns the HTTP handler that serves the metrics.
+func (e *Exporter) Handler() http.Handler {
+	return promhttp.HandlerFor(e.registry, promhttp.HandlerOpts{})
+}
+
+// Run polls the quota cost until the context is cancelled. The first poll is done immediately.
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		e.Poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll updates the metrics of every user once. A poll doesn't last longer than the interval, so
+// that a slow API doesn't pile up the polls.
+func (e *Exporter) Poll(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, e.interval)
+	defer cancel()
+	for _, username := range e.usernames {
+		e.scrapes.WithLabelValues(username).Inc()
+		if err := e.pollUser(ctx, username); err != nil {
+			e.scrapeErrors.WithLabelValues(username).Inc()
+			slog.WarnContext(ctx, "Failed to poll the quota cost", "user", username, "error", err)
+		}
+	}
+}
+
+func (e *Exporter) pollUser(ctx context.Context, username string) error {
+	orgID, err := quota.GetOrgID(ctx, username)
+	if err != nil {
+		return err
+	}
+	usages, err := quota.OrgUsage(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	// Forget the quotas that aren't reported anymore:
+	org := prometheus.Labels{"org": orgID}
+	e.allowed.DeletePartialMatch(org)
+	e.consumed.DeletePartialMatch(org)
+	for _, usage := range usages {
+		e.allowed.WithLabelValues(orgID, usage.QuotaID, usage.SkuNames).Set(float64(usage.Allowed))
+		e.consumed.WithLabelValues(orgID, usage.QuotaID, usage.SkuNames).Set(float64(usage.Consumed))
+	}
+	e.lastScrape.WithLabelValues(username, orgID).SetToCurrentTime()
+	slog.DebugContext(ctx, "Polled the quota cost", "user", username, "org", orgID, "quotas", len(usages))
+	return nil
+}
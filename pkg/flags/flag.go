@@ -3,10 +3,54 @@ package flags
 import (
 	"github.com/spf13/pflag"
 
+	"github/yasun1/myquota/pkg/audit"
+	"github/yasun1/myquota/pkg/config"
+	"github/yasun1/myquota/pkg/connection"
+	"github/yasun1/myquota/pkg/history"
+	"github/yasun1/myquota/pkg/lease"
+	"github/yasun1/myquota/pkg/logs"
 	"github/yasun1/myquota/pkg/logs/debug"
+	"github/yasun1/myquota/pkg/timeout"
 )
 
+// AddConnectionFlags adds the authentication flags, like '--auth' and '--token-file', and the
+// transport flags, like '--url' and '--insecure', to the given set of command line flags.
+func AddConnectionFlags(fs *pflag.FlagSet) {
+	connection.AddFlags(fs)
+}
+
+// AddConfigFlags adds the '--config' and '--profile' flags to the given set of command line flags.
+func AddConfigFlags(fs *pflag.FlagSet) {
+	config.AddFlags(fs)
+}
+
 // AddDebugFlag adds the '--debug' flag to the given set of command line flags.
 func AddDebugFlag(fs *pflag.FlagSet) {
 	debug.AddFlag(fs)
 }
+
+// AddLogFlags adds the '--log-level', '--log-format' and '--log-file' flags to the given set of
+// command line flags.
+func AddLogFlags(fs *pflag.FlagSet) {
+	logs.AddFlags(fs)
+}
+
+// AddTimeoutFlag adds the '--timeout' flag to the given set of command line flags.
+func AddTimeoutFlag(fs *pflag.FlagSet) {
+	timeout.AddFlag(fs)
+}
+
+// AddHistoryFlag adds the '--history-file' flag to the given set of command line flags.
+func AddHistoryFlag(fs *pflag.FlagSet) {
+	history.AddFlag(fs)
+}
+
+// AddLeaseFlag adds the '--lease-file' flag to the given set of command line flags.
+func AddLeaseFlag(fs *pflag.FlagSet) {
+	lease.AddFlag(fs)
+}
+
+// AddAuditFlag adds the '--audit-file' flag to the given set of command line flags.
+func AddAuditFlag(fs *pflag.FlagSet) {
+	audit.AddFlag(fs)
+}
@@ -0,0 +1,186 @@
+package group
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github/yasun1/myquota/pkg/config"
+	"github/yasun1/myquota/pkg/quota"
+
+	"github.com/spf13/pflag"
+)
+
+var (
+	name        string
+	concurrency int
+)
+
+// AddFlags adds the '--group' and '--concurrency' flags to the given set of command line flags.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(
+		&name,
+		"group",
+		"g",
+		"",
+		"The group of organizations in the configuration file. The command is run for every organization "+
+			"of the group instead of the organization of the account.",
+	)
+	flags.IntVar(
+		&concurrency,
+		"concurrency",
+		4,
+		"The maximum number of organizations of the group processed at the same time.",
+	)
+}
+
+// Name returns the name of the selected group, or an empty string if no group is selected.
+func Name() string {
+	return name
+}
+
+// Member is an organization of a group. The id of the organizations given by the username of
+// their account is resolved by Run.
+type Member struct {
+	Username string
+	OrgID    string
+}
+
+// Label returns the name of the organization in the tables.
+func (m Member) Label() string {
+	switch {
+	case m.Username == "":
+		return m.OrgID
+	case m.OrgID == "":
+		return m.Username
+	default:
+		return fmt.Sprintf("%s (%s)", m.Username, m.OrgID)
+	}
+}
+
+// Members returns the organizations of the selected group, the ones given by username first.
+func Members() ([]Member, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	g, err := cfg.Group(name)
+	if err != nil {
+		return nil, err
+	}
+	var members []Member
+	for _, username := range g.Usernames {
+		members = append(members, Member{Username: username})
+	}
+	for _, orgID := range g.OrgIDs {
+		members = append(members, Member{OrgID: orgID})
+	}
+	return members, nil
+}
+
+// ReadMembers returns the organizations of the accounts listed in the file, one username per line.
+// The empty lines and the lines starting with '#' are ignored.
+func ReadMembers(file string) ([]Member, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("[E] Failed to read the users file: %w", err)
+	}
+	var members []Member
+	for _, line := range strings.Split(string(data), "\n") {
+		username := strings.TrimSpace(line)
+		if username == "" || strings.HasPrefix(username, "#") {
+			continue
+		}
+		members = append(members, Member{Username: username})
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("[E] The users file '%s' is empty", file)
+	}
+	return members, nil
+}
+
+// Result is the outcome of a function run for an organization of a group.
+type Result[T any] struct {
+	Member
+	Value T
+	Err   error
+}
+
+// Run runs the function for every organization, with at most 'concurrency' of them at the same
+// time, and returns the results in the order of the members. A failure doesn't stop the others.
+func Run[T any](ctx context.Context, members []Member, fn func(ctx context.Context, member Member) (T, error)) []Result[T] {
+	limit := concurrency
+	if limit < 1 {
+		limit = 1
+	}
+	slots := make(chan struct{}, limit)
+	results := make([]Result[T], len(members))
+	var wg sync.WaitGroup
+	for i, member := range members {
+		wg.Add(1)
+		go func(result *Result[T], member Member) {
+			defer wg.Done()
+			result.Member = member
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				result.Err = ctx.Err()
+				return
+			}
+			if member.OrgID == "" {
+				orgID, err := quota.GetOrgID(ctx, member.Username)
+				if err != nil {
+					result.Err = err
+					return
+				}
+				member.OrgID = orgID
+				result.Member = member
+			}
+			result.Value, result.Err = fn(ctx, member)
+		}(&results[i], member)
+	}
+	wg.Wait()
+	return results
+}
+
+// FPrintErrors prints the errors of the organizations that failed, and returns how many failed.
+func FPrintErrors[T any](results []Result[T]) int {
+	failed := 0
+	writer := tabwriter.NewWriter(os.Stderr, 0, 0, 8, ' ', 0)
+	for _, result := range results {
+		if result.Err == nil {
+			continue
+		}
+		if failed == 0 {
+			fmt.Fprintf(os.Stderr, "\n>>> The errors of the organizations: \n")
+			fmt.Fprintf(writer, "Org\tError\t\n")
+		}
+		failed++
+		// The errors may quote the response, keep them on the row of the organization:
+		fmt.Fprintf(writer, "%s\t%s\n", result.Label(), strings.ReplaceAll(result.Err.Error(), "\n", " "))
+	}
+	writer.Flush()
+	if failed != 0 {
+		fmt.Fprintf(os.Stderr, "[E] Failed for %d of %d organizations.\n", failed, len(results))
+	}
+	return failed
+}
+
+// FPrintUsage prints the usages of the organizations of the group in one table, with the free
+// quota and the utilization, colored if color is true.
+func FPrintUsage(results []Result[[]quota.Usage], color bool) error {
+	fmt.Printf("\n>>> The quota of the group %s: \n", name)
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 8, ' ', 0)
+	fmt.Fprintf(writer, "Org\t%s\t\n", strings.Join(quota.UsageHeader, "\t"))
+	for _, result := range results {
+		for _, usage := range result.Value {
+			cells := append([]string{result.Label()}, quota.UsageCells(usage, color)...)
+			fmt.Fprintf(writer, "%s\n", strings.Join(cells, "\t"))
+		}
+	}
+	return writer.Flush()
+}
@@ -14,7 +14,8 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// This file contains helper functions for the tests.
+// This file contains helper functions to read and build JSON documents. They return errors
+// instead of asserting, the test assertions are in the 'testhelpers' package.
 
 package helpers
 
@@ -22,25 +23,21 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strconv"
 	"strings"
 	"text/template"
 	"time"
-
-	// nolint
-	. "github.com/onsi/gomega"
-
-	client "github.com/openshift-online/ocm-sdk-go"
 )
 
 // Parse parses the given JSON data and returns a map of strings containing the result.
-func Parse(data []byte) map[string]interface{} {
+func Parse(data []byte) (map[string]interface{}, error) {
 	var object map[string]interface{}
 	err := json.Unmarshal(data, &object)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return object
+	return object, nil
 }
 
 // DigString tries to find an attribute inside the given object with the given path, and returns its
@@ -121,46 +118,54 @@ func DigBool(object interface{}, keys ...interface{}) bool {
 }
 
 // DigInt tries to find an attribute inside the given object with the given path, and returns its
-// value, assuming that it is an integer. If there is no attribute with the given path then the test
-// will be aborted with an error.
-func DigInt(object interface{}, keys ...interface{}) int {
-	value := dig(object, keys)
-	// ExpectWithOffset(1, value).ToNot(BeNil())
-	var result float64
-	// ExpectWithOffset(1, value).To(BeAssignableToTypeOf(result))
-	result = value.(float64)
-	return int(result)
+// value, assuming that it is an integer. If there is no attribute with the given path, or if it
+// isn't a number, then an error is returned.
+func DigInt(object interface{}, keys ...interface{}) (int, error) {
+	result, err := DigFloat(object, keys...)
+	if err != nil {
+		return 0, err
+	}
+	return int(result), nil
 }
 
 // DigFloat tries to find an attribute inside the given object with the given path, and returns its
-// value, assuming that it is an floating point number. If there is no attribute with the given path
-// then the test will be aborted with an error.
-func DigFloat(object interface{}, keys ...interface{}) float64 {
-	value := dig(object, keys)
-	ExpectWithOffset(1, value).ToNot(BeNil())
-	var result float64
-	ExpectWithOffset(1, value).To(BeAssignableToTypeOf(result))
-	result = value.(float64)
-	return result
+// value, assuming that it is an floating point number. If there is no attribute with the given path,
+// or if it isn't a number, then an error is returned.
+func DigFloat(object interface{}, keys ...interface{}) (float64, error) {
+	value, err := DigObject(object, keys...)
+	if err != nil {
+		return 0, err
+	}
+	result, ok := value.(float64)
+	if !ok {
+		return 0, fmt.Errorf("the attribute %v is a %T, not a number", keys, value)
+	}
+	return result, nil
 }
 
 // DigObject tries to find an attribute inside the given object with the given path, and returns its
-// value. If there is no attribute with the given path then the test will be aborted with an error.
-func DigObject(object interface{}, keys ...interface{}) interface{} {
+// value. If there is no attribute with the given path then an error is returned.
+func DigObject(object interface{}, keys ...interface{}) (interface{}, error) {
 	value := dig(object, keys)
-	ExpectWithOffset(1, value).ToNot(BeNil())
-	return value
+	if value == nil {
+		return nil, fmt.Errorf("the attribute %v doesn't exist", keys)
+	}
+	return value, nil
 }
 
 // DigArray tries to find an array inside the given object with the given path, and returns its
-// value. If there is no attribute with the given path then the test will be aborted with an error.
-func DigArray(object interface{}, keys ...interface{}) []interface{} {
-	value := dig(object, keys)
-	//ExpectWithOffset(1, value).ToNot(BeNil())
-	var result []interface{}
-	//ExpectWithOffset(1, value).To(BeAssignableToTypeOf(result))
-	result = value.([]interface{})
-	return result
+// value. If there is no attribute with the given path, or if it isn't an array, then an error is
+// returned.
+func DigArray(object interface{}, keys ...interface{}) ([]interface{}, error) {
+	value, err := DigObject(object, keys...)
+	if err != nil {
+		return nil, err
+	}
+	result, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the attribute %v is a %T, not an array", keys, value)
+	}
+	return result, nil
 }
 
 func dig(object interface{}, keys []interface{}) interface{} {
@@ -180,6 +185,9 @@ func dig(object interface{}, keys []interface{}) interface{} {
 	case int:
 		switch data := object.(type) {
 		case []interface{}:
+			if key < 0 || key >= len(data) {
+				return nil
+			}
 			value := data[key]
 			if len(keys) == 1 {
 				return value
@@ -213,23 +221,24 @@ func dig(object interface{}, keys []interface{}) interface{} {
 //			"id": "4"
 //		}
 //	}
-func Template(source string, args ...interface{}) string {
+func Template(source string, args ...interface{}) (string, error) {
 	// Check that there is an even number of args, and that the first of each pair is an string:
 	count := len(args)
-	ExpectWithOffset(1, count%2).To(
-		Equal(0),
-		"Template '%s' should have an even number of arguments, but it has %d",
-		source, count,
-	)
+	if count%2 != 0 {
+		return "", fmt.Errorf(
+			"template '%s' should have an even number of arguments, but it has %d",
+			source, count,
+		)
+	}
 	for i := 0; i < count; i = i + 2 {
 		name := args[i]
-		_, ok := name.(string)
-		ExpectWithOffset(1, ok).To(
-			BeTrue(),
-			"Argument %d of template '%s' is a key, so it should be a string, "+
-				"but its type is %T",
-			i, source, name,
-		)
+		if _, ok := name.(string); !ok {
+			return "", fmt.Errorf(
+				"argument %d of template '%s' is a key, so it should be a string, "+
+					"but its type is %T",
+				i, source, name,
+			)
+		}
 	}
 
 	// Put the variables in the map that will be passed as the data object for the execution of
@@ -243,56 +252,17 @@ func Template(source string, args ...interface{}) string {
 
 	// Parse the template:
 	tmpl, err := template.New("").Parse(source)
-	ExpectWithOffset(1, err).ToNot(
-		HaveOccurred(),
-		"Can't parse template '%s': %v",
-		source, err,
-	)
+	if err != nil {
+		return "", fmt.Errorf("can't parse template '%s': %w", source, err)
+	}
 
 	// Execute the template:
 	buffer := new(bytes.Buffer)
 	err = tmpl.Execute(buffer, data)
-	ExpectWithOffset(1, err).ToNot(
-		HaveOccurred(),
-		"Can't execute template '%s': %v",
-		source, err,
-	)
-	return buffer.String()
-}
-
-func readGetResult(response *client.Response, err error, expectedStatus int) map[string]interface{} {
-	checkGetResponse(response, err, expectedStatus)
-	return Parse(response.Bytes())
-}
-
-func checkGetResponse(response *client.Response, err error, expectedStatus int) {
-	Expect(err).ToNot(HaveOccurred())
-	Expect(response.Status()).To(Equal(expectedStatus))
-}
-
-func readPostResult(response *client.Response, err error, expectedStatus int) map[string]interface{} {
-	checkPostResponse(response, err, expectedStatus)
-	return Parse(response.Bytes())
-}
-
-func checkPostResponse(response *client.Response, err error, expectedStatus int) {
-	Expect(err).ToNot(HaveOccurred())
-	Expect(response.Status()).To(Equal(expectedStatus))
-}
-
-func checkDeleteResponse(response *client.Response, err error, expectedStatus int) {
-	Expect(err).ToNot(HaveOccurred())
-	Expect(response.Status()).To(Equal(expectedStatus))
-}
-
-func readPatchResponse(response *client.Response, err error, expectedStatus int) map[string]interface{} {
-	checkPatchResponse(response, err, expectedStatus)
-	return Parse(response.Bytes())
-}
-
-func checkPatchResponse(response *client.Response, err error, expectedStatus int) {
-	Expect(err).ToNot(HaveOccurred())
-	Expect(response.Status()).To(Equal(expectedStatus))
+	if err != nil {
+		return "", fmt.Errorf("can't execute template '%s': %w", source, err)
+	}
+	return buffer.String(), nil
 }
 
 func isLocal(url string) bool {
@@ -310,15 +280,15 @@ func isLocal(url string) bool {
 func runAttempt(attempt func() (interface{}, bool), maxAttempts int, delay time.Duration) (interface{}, error) {
 	var result interface{}
 	for i := 0; i < maxAttempts; i++ {
-		fmt.Println("Running attempt", i)
+		slog.Debug("Running attempt", "attempt", i)
 		result, toContinue := attempt()
 		if toContinue {
-			fmt.Println("Need to continue for another attempt")
+			slog.Debug("Need to continue for another attempt", "attempt", i)
 		} else {
-			fmt.Println("Attempt successful, returning result")
+			slog.Debug("Attempt successful, returning result", "attempt", i)
 			return result, nil
 		}
-		fmt.Println("Sleeping for", delay)
+		slog.Debug("Sleeping before the next attempt", "delay", delay)
 		time.Sleep(delay)
 	}
 	return result, fmt.Errorf("Got to max attempts %d", maxAttempts)
@@ -142,21 +142,27 @@ func ConvertStringToInt(mystring string) int {
 	return myint
 }
 
-func ConvertStructToMap(s interface{}) map[string]interface{} {
+func ConvertStructToMap(s interface{}) (map[string]interface{}, error) {
 	structMap := make(map[string]interface{})
 
-	j, _ := json.Marshal(s)
-	err := json.Unmarshal(j, &structMap)
+	j, err := json.Marshal(s)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	err = json.Unmarshal(j, &structMap)
+	if err != nil {
+		return nil, err
 	}
 
-	return structMap
+	return structMap, nil
 }
 
-func ConvertStructToString(s interface{}) string {
-	structMap := ConvertStructToMap(s)
-	return ConvertMapToJSONString(structMap)
+func ConvertStructToString(s interface{}) (string, error) {
+	structMap, err := ConvertStructToMap(s)
+	if err != nil {
+		return "", err
+	}
+	return ConvertMapToJSONString(structMap), nil
 }
 
 func IsInMap(inputMap map[string]interface{}, key string) bool {
@@ -1,15 +1,9 @@
 package helpers
 
 import (
-
-	// nolint
 	"encoding/json"
 	"fmt"
 	"strings"
-
-	. "github.com/onsi/gomega"
-
-	client "github.com/openshift-online/ocm-sdk-go"
 )
 
 const (
@@ -23,12 +17,6 @@ func Dig(object interface{}, keys []interface{}) interface{} {
 	return dig(object, keys)
 }
 
-// CheckResponse is used to checking
-func CheckResponse(response *client.Response, err error, expectedStatus int) {
-	Expect(err).ToNot(HaveOccurred())
-	Expect(response.Status()).To(Equal(expectedStatus))
-}
-
 // FlatMap parses the data, and stores all the attributes and the sub attributes at the same level with the prefix key.
 func FlatMap(inputMap map[string]interface{}, outputMap map[string]interface{}, key string, connector string) {
 	if len(inputMap) == 0 {
@@ -86,7 +74,10 @@ func FlatInitialMap(inputMap map[string]interface{}, connector string) map[strin
 
 // ConvertRequestBodyByAttr will return the request bodies by attributes
 func ConvertRequestBodyByAttr(inputString string, connector string) (outArray []string, err error) {
-	inputMap := Parse([]byte(inputString))
+	inputMap, err := Parse([]byte(inputString))
+	if err != nil {
+		return
+	}
 	outMap := FlatInitialMap(inputMap, connector)
 	outArray = ConvertFlatMapToArray(outMap, connector)
 	return
@@ -0,0 +1,123 @@
+package history
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+// Forecast is the estimation of when the consumption of a quota reaches the allowed quota, at
+// the current growth rate.
+type Forecast struct {
+	QuotaID  string
+	SkuNames string
+	Latest   Sample
+	// GrowthPerDay is the slope of the least squares fit of the consumption.
+	GrowthPerDay float64
+	// Fitted is false when there are not enough samples to fit the trend.
+	Fitted bool
+	// Exhausted is the estimated time when the quota runs out, it is zero when the consumption
+	// isn't growing.
+	Exhausted time.Time
+}
+
+// Exhausts returns whether the quota is estimated to run out within the duration after now.
+func (f Forecast) Exhausts(now time.Time, within time.Duration) bool {
+	return !f.Exhausted.IsZero() && f.Exhausted.Before(now.Add(within))
+}
+
+// ForecastAll fits the trend of the consumption of each quota of the samples, ordered by quota id.
+// The quotas that are neither allowed nor consumed in the latest sample are left out, like the
+// check of the usage does, as the organization was never granted them.
+func ForecastAll(samples []Sample) []Forecast {
+	quotaIDs, groups := GroupByQuota(samples)
+	var forecasts []Forecast
+	for _, quotaID := range quotaIDs {
+		group := groups[quotaID]
+		if latest := group[len(group)-1]; latest.Allowed == 0 && latest.Consumed == 0 {
+			continue
+		}
+		forecasts = append(forecasts, forecast(group))
+	}
+	return forecasts
+}
+
+func forecast(samples []Sample) Forecast {
+	latest := samples[len(samples)-1]
+	result := Forecast{
+		QuotaID:  latest.QuotaID,
+		SkuNames: latest.SkuNames,
+		Latest:   latest,
+	}
+	if latest.Headroom() <= 0 {
+		result.Exhausted = latest.Time
+	}
+
+	// Least squares fit of the consumption against the days since the first sample:
+	origin := samples[0].Time
+	var n, sumX, sumY, sumXY, sumXX float64
+	for _, sample := range samples {
+		x := sample.Time.Sub(origin).Hours() / 24
+		y := float64(sample.Consumed)
+		n++
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	denominator := n*sumXX - sumX*sumX
+	if n < 2 || denominator == 0 {
+		return result
+	}
+	result.Fitted = true
+	result.GrowthPerDay = (n*sumXY - sumX*sumY) / denominator
+
+	if latest.Headroom() > 0 && result.GrowthPerDay > 0 {
+		days := float64(latest.Headroom()) / result.GrowthPerDay
+		// Beyond a hundred years the estimation is meaningless, and overflows time.Duration:
+		if days < 100*365 {
+			result.Exhausted = latest.Time.Add(time.Duration(days * 24 * float64(time.Hour)))
+		}
+	}
+	return result
+}
+
+// FPrintForecasts prints the forecast of each quota, and flags the ones that run out within the duration.
+func FPrintForecasts(forecasts []Forecast, now time.Time, within time.Duration) error {
+	fmt.Printf("\n>>> The forecast of the quota: \n")
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 8, ' ', 0)
+	fmt.Fprintf(writer, "Name\tQuotaID\tAllowed\tConsumed\tGrowthPerDay\tExhausted\tDaysLeft\tStatus\t\n")
+	for _, forecast := range forecasts {
+		growth := "-"
+		if forecast.Fitted {
+			growth = fmt.Sprintf("%.2f", forecast.GrowthPerDay)
+		}
+		exhausted, daysLeft := "-", "-"
+		if !forecast.Exhausted.IsZero() {
+			exhausted = forecast.Exhausted.Local().Format(time.RFC3339)
+			daysLeft = fmt.Sprintf("%.1f", math.Max(forecast.Exhausted.Sub(now).Hours()/24, 0))
+		}
+		status := "OK"
+		switch {
+		case forecast.Latest.Headroom() <= 0:
+			status = "EXHAUSTED"
+		case forecast.Exhausts(now, within):
+			status = "AT RISK"
+		case !forecast.Fitted:
+			status = "UNKNOWN"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%d\t%d\t%s\t%s\t%s\t%s\n",
+			forecast.SkuNames,
+			forecast.QuotaID,
+			forecast.Latest.Allowed,
+			forecast.Latest.Consumed,
+			growth,
+			exhausted,
+			daysLeft,
+			status,
+		)
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,31 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func TestForecastAllSkipsTheQuotasNeverGranted(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	var samples []Sample
+	for day := 0; day < 3; day++ {
+		at := now.Add(time.Duration(day-2) * 24 * time.Hour)
+		samples = append(samples,
+			Sample{Time: at, QuotaID: "addon|rhoam", SkuNames: "MCT4249"},
+			Sample{Time: at, QuotaID: "cluster|byoc|osd", SkuNames: "MCT3326", Allowed: 10, Consumed: 2 + day},
+			Sample{Time: at, QuotaID: "cluster|byoc|rosa", SkuNames: "MCT4260", Allowed: 2, Consumed: 2},
+		)
+	}
+
+	forecasts := ForecastAll(samples)
+	if len(forecasts) != 2 || forecasts[0].QuotaID != "cluster|byoc|osd" || forecasts[1].QuotaID != "cluster|byoc|rosa" {
+		t.Fatalf("expected the forecasts of the granted quotas only, got %+v", forecasts)
+	}
+	within := 7 * 24 * time.Hour
+	if !forecasts[0].Exhausts(now, within) || forecasts[0].GrowthPerDay != 1 {
+		t.Errorf("expected 'cluster|byoc|osd' to run out in 6 days at 1 per day, got %+v", forecasts[0])
+	}
+	if !forecasts[1].Exhausts(now, within) {
+		t.Errorf("expected 'cluster|byoc|rosa' to be exhausted, got %+v", forecasts[1])
+	}
+}
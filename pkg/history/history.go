@@ -0,0 +1,214 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+var file string
+
+// AddFlag adds the '--history-file' flag to the given set of command line flags.
+func AddFlag(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&file,
+		"history-file",
+		os.Getenv("MYQUOTA_HISTORY"),
+		"The file the usage samples are recorded to, in JSON lines. The default is the value of "+
+			"'MYQUOTA_HISTORY', or 'myquota/history.jsonl' in the user configuration directory.",
+	)
+}
+
+// Path returns the location of the history file.
+func Path() (string, error) {
+	if file != "" {
+		return file, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("[E] Failed to find the configuration directory: %w", err)
+	}
+	return filepath.Join(configDir, "myquota", "history.jsonl"), nil
+}
+
+// Sample is the usage of a quota in an organization of an OCM environment at a point in time.
+type Sample struct {
+	Time time.Time `json:"time"`
+	// Environment is the name of the OCM environment, or the URL of its API gateway.
+	Environment string `json:"environment,omitempty"`
+	Username    string `json:"username"`
+	OrgID       string `json:"org_id"`
+	QuotaID     string `json:"quota_id"`
+	SkuNames    string `json:"sku"`
+	Allowed     int    `json:"allowed"`
+	Consumed    int    `json:"consumed"`
+}
+
+// Headroom returns the number of the resources that can still be used.
+func (s Sample) Headroom() int {
+	return s.Allowed - s.Consumed
+}
+
+// Matches returns whether the sku is one of the skus of the quota, or is the quota id, so that the
+// quotas that are consumed without an assigned sku can be selected too.
+func (s Sample) Matches(sku string) bool {
+	return s.QuotaID == sku || s.HasSku(sku)
+}
+
+// HasSku returns whether the sku is one of the skus of the quota.
+func (s Sample) HasSku(sku string) bool {
+	for _, name := range strings.Split(s.SkuNames, ",") {
+		if name == sku {
+			return true
+		}
+	}
+	return false
+}
+
+// Append adds the samples to the end of the history file, creating it if it doesn't exist. The
+// samples are written with a single write, so that the runs of concurrent jobs don't interleave.
+func Append(samples []Sample) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	for _, sample := range samples {
+		if err = encoder.Encode(sample); err != nil {
+			return err
+		}
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("[E] Failed to create the history directory: %w", err)
+	}
+	historyFile, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("[E] Failed to open the history file: %w", err)
+	}
+	_, err = historyFile.Write(buffer.Bytes())
+	if closeErr := historyFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("[E] Failed to write the history file: %w", err)
+	}
+	return nil
+}
+
+// Read returns the samples of the user recorded in the environment since the given time, ordered
+// by time. If skus are given, only the samples of the quotas of those skus or quota ids are
+// returned. The samples recorded without an environment are ignored, as they can't be told apart.
+func Read(environment string, username string, since time.Time, skus ...string) ([]Sample, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	historyFile, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("[E] The history file '%s' doesn't exist, please run 'myquota record' first", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[E] Failed to open the history file: %w", err)
+	}
+	defer historyFile.Close()
+
+	var samples []Sample
+	scanner := bufio.NewScanner(historyFile)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var sample Sample
+		if err = json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+			return nil, fmt.Errorf("[E] Failed to parse the line %d of the history file '%s': %w", line, path, err)
+		}
+		if sample.Environment != environment || sample.Username != username || sample.Time.Before(since) {
+			continue
+		}
+		if len(skus) != 0 && !hasAnySku(sample, skus) {
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("[E] Failed to read the history file: %w", err)
+	}
+
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].Time.Before(samples[j].Time)
+	})
+	return samples, nil
+}
+
+func hasAnySku(sample Sample, skus []string) bool {
+	for _, sku := range skus {
+		if sample.Matches(sku) {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupByQuota splits the samples by quota id, keeping their order. The quota ids are returned sorted.
+func GroupByQuota(samples []Sample) ([]string, map[string][]Sample) {
+	groups := make(map[string][]Sample)
+	for _, sample := range samples {
+		groups[sample.QuotaID] = append(groups[sample.QuotaID], sample)
+	}
+	quotaIDs := make([]string, 0, len(groups))
+	for quotaID := range groups {
+		quotaIDs = append(quotaIDs, quotaID)
+	}
+	sort.Strings(quotaIDs)
+	return quotaIDs, groups
+}
+
+// Summary is the peak usage of a quota over a window.
+type Summary struct {
+	QuotaID     string
+	SkuNames    string
+	Samples     int
+	Peak        int
+	PeakTime    time.Time
+	MinHeadroom int
+	Latest      Sample
+}
+
+// Summarize returns the summary of each quota of the samples, ordered by quota id.
+func Summarize(samples []Sample) []Summary {
+	quotaIDs, groups := GroupByQuota(samples)
+	var summaries []Summary
+	for _, quotaID := range quotaIDs {
+		group := groups[quotaID]
+		summary := Summary{
+			QuotaID:     quotaID,
+			Samples:     len(group),
+			Peak:        group[0].Consumed,
+			PeakTime:    group[0].Time,
+			MinHeadroom: group[0].Headroom(),
+			Latest:      group[len(group)-1],
+		}
+		summary.SkuNames = summary.Latest.SkuNames
+		for _, sample := range group[1:] {
+			if sample.Consumed > summary.Peak {
+				summary.Peak = sample.Consumed
+				summary.PeakTime = sample.Time
+			}
+			if sample.Headroom() < summary.MinHeadroom {
+				summary.MinHeadroom = sample.Headroom()
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
@@ -0,0 +1,45 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+// FPrintSeries prints the samples as a time series.
+func FPrintSeries(samples []Sample) error {
+	fmt.Printf("\n>>> The usage samples: \n")
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 8, ' ', 0)
+	fmt.Fprintf(writer, "Time\tName\tQuotaID\tAllowed\tConsumed\tHeadroom\t\n")
+	for _, sample := range samples {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%d\t%d\t%d\n",
+			sample.Time.Local().Format(time.RFC3339),
+			sample.SkuNames,
+			sample.QuotaID,
+			sample.Allowed,
+			sample.Consumed,
+			sample.Headroom(),
+		)
+	}
+	return writer.Flush()
+}
+
+// FPrintSummaries prints the peak usage of each quota.
+func FPrintSummaries(summaries []Summary, window time.Duration) error {
+	fmt.Printf("\n>>> The peak usage over the last %s: \n", window)
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 8, ' ', 0)
+	fmt.Fprintf(writer, "Name\tQuotaID\tSamples\tAllowed\tPeakConsumed\tPeakTime\tMinHeadroom\t\n")
+	for _, summary := range summaries {
+		fmt.Fprintf(writer, "%s\t%s\t%d\t%d\t%d\t%s\t%d\n",
+			summary.SkuNames,
+			summary.QuotaID,
+			summary.Samples,
+			summary.Latest.Allowed,
+			summary.Peak,
+			summary.PeakTime.Local().Format(time.RFC3339),
+			summary.MinHeadroom,
+		)
+	}
+	return writer.Flush()
+}
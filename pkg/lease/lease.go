@@ -0,0 +1,197 @@
+package lease
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	"golang.org/x/sys/unix"
+)
+
+var file string
+
+// AddFlag adds the '--lease-file' flag to the given set of command line flags.
+func AddFlag(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&file,
+		"lease-file",
+		os.Getenv("MYQUOTA_LEASES"),
+		"The file the leases of the resource quota are stored in. The default is the value of "+
+			"'MYQUOTA_LEASES', or 'myquota/leases.json' in the user configuration directory.",
+	)
+}
+
+// Path returns the location of the lease file.
+func Path() (string, error) {
+	if file != "" {
+		return file, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("[E] Failed to find the configuration directory: %w", err)
+	}
+	return filepath.Join(configDir, "myquota", "leases.json"), nil
+}
+
+// Lease records a temporary change of a resource quota, so that it can be reverted when it expires.
+type Lease struct {
+	ID string `json:"id"`
+	// Environment is the environment, or the URL, of the gateway the resource quota is assigned in.
+	Environment string `json:"environment,omitempty"`
+	Username    string `json:"username"`
+	OrgID       string `json:"org_id"`
+	Sku         string `json:"sku"`
+	QuotaID     string `json:"quota_id"`
+	Type        string `json:"type"`
+	// Existed is whether the resource quota was assigned before the lease, and Previous its count.
+	Existed  bool      `json:"existed"`
+	Previous int       `json:"previous"`
+	Count    int       `json:"count"`
+	Owner    string    `json:"owner"`
+	Reason   string    `json:"reason,omitempty"`
+	Created  time.Time `json:"created"`
+	Expires  time.Time `json:"expires"`
+}
+
+// Expired returns whether the lease is expired at the given time.
+func (l *Lease) Expired(now time.Time) bool {
+	return !now.Before(l.Expires)
+}
+
+// Store is the content of the lease file.
+type Store struct {
+	Leases []*Lease `json:"leases"`
+}
+
+// lock serializes the updates of the lease file in the process, as the commands of a group update it
+// from several goroutines.
+var lock sync.Mutex
+
+// Lock takes an exclusive lock on the lease file, held until the returned function is called, so
+// that the commands running at the same time don't lose the leases of each other. It is taken
+// before loading the store, and released after saving it.
+func Lock() (func(), error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("[E] Failed to create the lease directory: %w", err)
+	}
+	lock.Lock()
+	file, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		lock.Unlock()
+		return nil, fmt.Errorf("[E] Failed to lock the lease file: %w", err)
+	}
+	if err = unix.Flock(int(file.Fd()), unix.LOCK_EX); err != nil {
+		file.Close()
+		lock.Unlock()
+		return nil, fmt.Errorf("[E] Failed to lock the lease file: %w", err)
+	}
+	return func() {
+		unix.Flock(int(file.Fd()), unix.LOCK_UN)
+		file.Close()
+		lock.Unlock()
+	}, nil
+}
+
+// Load reads the lease file. A missing file is the same as an empty store.
+func Load() (*Store, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	store := &Store{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[E] Failed to read the lease file: %w", err)
+	}
+	if err = json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("[E] Failed to parse the lease file '%s': %w", path, err)
+	}
+	return store, nil
+}
+
+// Save writes the store to the lease file. The file is replaced atomically, so that an
+// interrupted write doesn't lose the leases.
+func (s *Store) Save() error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(s.Leases, func(i, j int) bool {
+		return s.Leases[i].Expires.Before(s.Leases[j].Expires)
+	})
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("[E] Failed to create the lease directory: %w", err)
+	}
+	temp := path + ".tmp"
+	if err = os.WriteFile(temp, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("[E] Failed to write the lease file: %w", err)
+	}
+	if err = os.Rename(temp, path); err != nil {
+		return fmt.Errorf("[E] Failed to write the lease file: %w", err)
+	}
+	return nil
+}
+
+// Find returns the lease of the sku and type in the organization of the environment, or nil if
+// there is none.
+func (s *Store) Find(environment string, orgID string, sku string, qtype string) *Lease {
+	for _, lease := range s.Leases {
+		if lease.Environment == environment && lease.OrgID == orgID && lease.Sku == sku && lease.Type == qtype {
+			return lease
+		}
+	}
+	return nil
+}
+
+// Put adds the lease to the store. If the resource quota is already leased, the existing lease is
+// renewed instead, and keeps the state of the resource quota before the first lease.
+func (s *Store) Put(lease *Lease) *Lease {
+	if existing := s.Find(lease.Environment, lease.OrgID, lease.Sku, lease.Type); existing != nil {
+		lease.ID = existing.ID
+		lease.Existed = existing.Existed
+		lease.Previous = existing.Previous
+		*existing = *lease
+		return existing
+	}
+	if lease.ID == "" {
+		lease.ID = newID()
+	}
+	s.Leases = append(s.Leases, lease)
+	return lease
+}
+
+// Delete removes the lease with the given id from the store.
+func (s *Store) Delete(id string) {
+	for i, lease := range s.Leases {
+		if lease.ID == id {
+			s.Leases = append(s.Leases[:i], s.Leases[i+1:]...)
+			return
+		}
+	}
+}
+
+func newID() string {
+	data := make([]byte, 4)
+	if _, err := rand.Read(data); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(data)
+}
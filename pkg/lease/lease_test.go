@@ -0,0 +1,51 @@
+package lease
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestLockKeepsTheLeasesOfConcurrentUpdates(t *testing.T) {
+	file = filepath.Join(t.TempDir(), "leases.json")
+	defer func() { file = "" }()
+
+	const updates = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, updates)
+	for i := 0; i < updates; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			unlock, err := Lock()
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer unlock()
+			store, err := Load()
+			if err != nil {
+				errs <- err
+				return
+			}
+			store.Put(&Lease{OrgID: "org1", Sku: fmt.Sprintf("MCT%d", i), Type: "Manual"})
+			errs <- store.Save()
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.Leases) != updates {
+		t.Errorf("expected %d leases, found %d", updates, len(store.Leases))
+	}
+}
@@ -0,0 +1,43 @@
+package lease
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// FPrintLeases prints the leases, and whether they are expired at the given time.
+func FPrintLeases(leases []*Lease, now time.Time) error {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 8, ' ', 0)
+	fmt.Fprintf(writer, "ID\tEnvironment\tUsername\tSku\tType\tPrevious\tCount\tOwner\tReason\tExpires\tStatus\t\n")
+	for _, lease := range leases {
+		previous := "-"
+		if lease.Existed {
+			previous = strconv.Itoa(lease.Previous)
+		}
+		environment := lease.Environment
+		if environment == "" {
+			environment = "-"
+		}
+		status := "active"
+		if lease.Expired(now) {
+			status = "expired"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+			lease.ID,
+			environment,
+			lease.Username,
+			lease.Sku,
+			lease.Type,
+			previous,
+			lease.Count,
+			lease.Owner,
+			lease.Reason,
+			lease.Expires.Local().Format(time.RFC3339),
+			status,
+		)
+	}
+	return writer.Flush()
+}
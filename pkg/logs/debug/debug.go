@@ -13,7 +13,7 @@ func AddFlag(flags *pflag.FlagSet) {
 		"debug",
 		"d",
 		false,
-		"If the debug is true, will print out the rich logs",
+		"If the debug is true, will print out the rich logs. It is the same as '--log-level=debug'.",
 	)
 }
 
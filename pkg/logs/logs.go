@@ -0,0 +1,79 @@
+package logs
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github/yasun1/myquota/pkg/logs/debug"
+)
+
+var (
+	level  string
+	format string
+	file   string
+)
+
+// AddFlags adds the logging flags to the given set of command line flags.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&level,
+		"log-level",
+		"info",
+		"The level of the logs, one of 'debug', 'info', 'warn' or 'error'.",
+	)
+	flags.StringVar(
+		&format,
+		"log-format",
+		"text",
+		"The format of the logs, one of 'text' or 'json'.",
+	)
+	flags.StringVar(
+		&file,
+		"log-file",
+		"",
+		"The file the logs are appended to. By default the logs are written to the standard error stream.",
+	)
+}
+
+// Setup replaces the default logger with the one described by the logging flags, so it must be
+// called after the flags are parsed. The option '--debug' and the variable 'OCM_Debug_Mode'
+// are kept as shortcuts of '--log-level=debug'.
+func Setup() error {
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("[E] The log level '%s' is invalid, valid levels are 'debug', 'info', 'warn' and 'error'", level)
+	}
+	if debug.DebugMode() || os.Getenv("OCM_Debug_Mode") == "true" {
+		logLevel = slog.LevelDebug
+	}
+
+	var writer io.Writer = os.Stderr
+	if file != "" {
+		logFile, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("[E] Failed to open the log file: %w", err)
+		}
+		writer = logFile
+	}
+
+	options := &slog.HandlerOptions{
+		Level: logLevel,
+	}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "text":
+		handler = slog.NewTextHandler(writer, options)
+	case "json":
+		handler = slog.NewJSONHandler(writer, options)
+	default:
+		return fmt.Errorf("[E] The log format '%s' is invalid, valid formats are 'text' and 'json'", format)
+	}
+
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
@@ -0,0 +1,124 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+// Environments are the known OCM environments.
+var Environments = []string{"production", "staging", "integration"}
+
+// IsEnvironment returns whether the name is a known OCM environment.
+func IsEnvironment(name string) bool {
+	return contains(Environments, name)
+}
+
+// Manifest is the declared state of the resource quotas of some organizations.
+type Manifest struct {
+	// Environment is the OCM environment the manifest applies to, etc, 'staging'.
+	Environment string `yaml:"environment,omitempty" json:"environment,omitempty"`
+	Orgs        []*Org `yaml:"orgs" json:"orgs"`
+}
+
+// Org is the declared state of the resource quotas of an organization. The organization is
+// identified by the id, or by the account when the id is empty. The name identifies the
+// organization across the base manifest and its overlays, as the accounts differ per environment.
+type Org struct {
+	Position `yaml:"-" json:"-"`
+	Name     string   `yaml:"name,omitempty" json:"name,omitempty"`
+	Username string   `yaml:"username,omitempty" json:"username,omitempty"`
+	OrgID    string   `yaml:"org_id,omitempty" json:"org_id,omitempty"`
+	Quotas   []*Quota `yaml:"quotas" json:"quotas"`
+}
+
+// Quota is a resource quota of an organization. In an overlay, Remove takes the resource quota
+// out of the base manifest.
+type Quota struct {
+	Position `yaml:"-" json:"-"`
+	Sku      string `yaml:"sku" json:"sku"`
+	Type     string `yaml:"type" json:"type"`
+	SkuCount int    `yaml:"sku_count" json:"sku_count"`
+	Remove   bool   `yaml:"remove,omitempty" json:"remove,omitempty"`
+
+	// hasCount is whether the count is set in the file, as zero is a valid count.
+	hasCount bool
+}
+
+// Key returns the key that identifies the organization in the base manifest and its overlays.
+func (o *Org) Key() string {
+	switch {
+	case o.Name != "":
+		return "name:" + o.Name
+	case o.Username != "":
+		return "username:" + o.Username
+	default:
+		return "org_id:" + o.OrgID
+	}
+}
+
+// Label returns the name of the organization in the messages.
+func (o *Org) Label() string {
+	label := o.Username
+	if label == "" {
+		label = o.OrgID
+	} else if o.OrgID != "" {
+		label = fmt.Sprintf("%s (%s)", o.Username, o.OrgID)
+	}
+	if o.Name != "" {
+		label = fmt.Sprintf("%s: %s", o.Name, label)
+	}
+	return label
+}
+
+// Sort orders the quotas of each organization by sku and type, so that the manifests are stable.
+func (m *Manifest) Sort() {
+	for _, org := range m.Orgs {
+		sort.SliceStable(org.Quotas, func(i, j int) bool {
+			if org.Quotas[i].Sku != org.Quotas[j].Sku {
+				return org.Quotas[i].Sku < org.Quotas[j].Sku
+			}
+			return org.Quotas[i].Type < org.Quotas[j].Type
+		})
+	}
+}
+
+// StripZero removes the quotas whose count is zero.
+func (m *Manifest) StripZero() {
+	for _, org := range m.Orgs {
+		quotas := []*Quota{}
+		for _, quota := range org.Quotas {
+			if quota.SkuCount != 0 {
+				quotas = append(quotas, quota)
+			}
+		}
+		org.Quotas = quotas
+	}
+}
+
+// Write writes the manifest in the given format.
+func (m *Manifest) Write(writer io.Writer, format string) error {
+	switch format {
+	case FormatYAML:
+		encoder := yaml.NewEncoder(writer)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(m); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case FormatJSON:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(m)
+	}
+	return fmt.Errorf("[E] The format '%s' is invalid, valid formats are '%s' and '%s'", format, FormatYAML, FormatJSON)
+}
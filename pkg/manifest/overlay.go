@@ -0,0 +1,199 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// variablePattern matches the references to the variables, etc, '${QUOTA_USER}'. A reference
+// preceded by another '$', etc, '$${QUOTA_USER}', is kept as is, without the first '$'.
+var variablePattern = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Interpolate replaces the references to the variables with their value in vars, or in the
+// environment variables. The references to undefined variables are returned as issues.
+func Interpolate(file string, data []byte, vars map[string]string) ([]byte, []Issue) {
+	var issues []Issue
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	for i, line := range lines {
+		lines[i] = variablePattern.ReplaceAllFunc(line, func(reference []byte) []byte {
+			if reference[1] == '$' {
+				return reference[1:]
+			}
+			name := string(reference[2 : len(reference)-1])
+			if value, found := vars[name]; found {
+				return []byte(value)
+			}
+			if value, found := os.LookupEnv(name); found {
+				return []byte(value)
+			}
+			issues = append(issues, Issue{
+				File:    file,
+				Line:    i + 1,
+				Column:  bytes.Index(line, reference) + 1,
+				Message: fmt.Sprintf("the variable '%s' is undefined, set it in the environment or with '--set'", name),
+			})
+			return reference
+		})
+	}
+	return bytes.Join(lines, nil), issues
+}
+
+// ParseVars parses the 'name=value' assignments of the option '--set'.
+func ParseVars(assignments []string) (map[string]string, error) {
+	vars := make(map[string]string)
+	for _, assignment := range assignments {
+		name, value, found := strings.Cut(assignment, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("[E] The variable '%s' is invalid, expected 'name=value'", assignment)
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+// OverlayPath returns the overlay of the base manifest for the environment, etc, 'quota.staging.yaml'
+// for 'quota.yaml', or an empty string if it doesn't exist.
+func OverlayPath(base string, environment string) string {
+	ext := filepath.Ext(base)
+	path := strings.TrimSuffix(base, ext) + "." + environment + ext
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// BasePath returns the base manifest of the overlay, etc, 'quota.yaml' for 'quota.staging.yaml', or
+// an empty string if the file isn't the overlay of an existing manifest. The environment of the
+// overlay is a known one, or the active one.
+func BasePath(file string, active string) string {
+	ext := filepath.Ext(file)
+	stem := strings.TrimSuffix(file, ext)
+	environment := strings.TrimPrefix(filepath.Ext(stem), ".")
+	if environment == "" || !IsEnvironment(environment) && environment != active {
+		return ""
+	}
+	base := strings.TrimSuffix(stem, "."+environment) + ext
+	if _, err := os.Stat(base); err != nil {
+		return ""
+	}
+	return base
+}
+
+// Overlay changes the manifest with the overlay: the organizations are matched by name, username
+// or id; their accounts and the counts of their resource quotas are replaced, the new resource
+// quotas are added, the removed ones are taken out, and the new organizations are added.
+func (m *Manifest) Overlay(overlay *Manifest) {
+	if overlay.Environment != "" {
+		m.Environment = overlay.Environment
+	}
+	orgs := make(map[string]*Org)
+	for _, org := range m.Orgs {
+		orgs[org.Key()] = org
+	}
+	for _, overlayOrg := range overlay.Orgs {
+		org, existed := orgs[overlayOrg.Key()]
+		if !existed {
+			org = &Org{
+				Position: overlayOrg.Position,
+				Name:     overlayOrg.Name,
+				Quotas:   []*Quota{},
+			}
+			orgs[overlayOrg.Key()] = org
+			m.Orgs = append(m.Orgs, org)
+		}
+		if overlayOrg.Username != "" {
+			org.Username = overlayOrg.Username
+		}
+		if overlayOrg.OrgID != "" {
+			org.OrgID = overlayOrg.OrgID
+		}
+
+		for _, overlayQuota := range overlayOrg.Quotas {
+			index := -1
+			for i, quota := range org.Quotas {
+				if quota.Sku == overlayQuota.Sku && quota.Type == overlayQuota.Type {
+					index = i
+					break
+				}
+			}
+			switch {
+			case overlayQuota.Remove && index >= 0:
+				org.Quotas = append(org.Quotas[:index], org.Quotas[index+1:]...)
+			case overlayQuota.Remove:
+			case index >= 0:
+				org.Quotas[index].SkuCount = overlayQuota.SkuCount
+				org.Quotas[index].Position = overlayQuota.Position
+			default:
+				quota := *overlayQuota
+				org.Quotas = append(org.Quotas, &quota)
+			}
+		}
+	}
+}
+
+// Load reads the base manifest and the overlay of the environment, interpolates the variables in
+// both, and returns the merged manifest. The overlay is the given file, or the file next to the
+// base manifest named after the environment. The issues of all the files are returned together.
+func Load(base string, overlay string, environment string, vars map[string]string) (*Manifest, []Issue, error) {
+	m, issues, err := ReadFile(base, vars, false)
+	if err != nil || m == nil {
+		return nil, issues, err
+	}
+	if m.Environment != "" && environment != "" && m.Environment != environment {
+		issues = append(issues, Issue{
+			File:    base,
+			Line:    1,
+			Column:  1,
+			Message: fmt.Sprintf("the manifest is for the environment '%s', but the active environment is '%s'", m.Environment, environment),
+		})
+	}
+
+	if overlay == "" && environment != "" {
+		overlay = OverlayPath(base, environment)
+	}
+	declared := len(m.Orgs)
+	if overlay != "" {
+		o, overlayIssues, err := ReadFile(overlay, vars, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		issues = append(issues, overlayIssues...)
+		if o == nil {
+			return nil, issues, nil
+		}
+		if o.Environment != "" && environment != "" && o.Environment != environment {
+			issues = append(issues, Issue{
+				File:    overlay,
+				Line:    1,
+				Column:  1,
+				Message: fmt.Sprintf("the overlay is for the environment '%s', but the active environment is '%s'", o.Environment, environment),
+			})
+		}
+		m.Overlay(o)
+	}
+
+	if environment != "" {
+		m.Environment = environment
+	}
+	for i, org := range m.Orgs {
+		if org.Name == "" || org.Username != "" || org.OrgID != "" {
+			continue
+		}
+		file := base
+		if i >= declared {
+			file = overlay
+		}
+		issues = append(issues, Issue{
+			File:    file,
+			Line:    org.Line,
+			Column:  org.Column,
+			Message: fmt.Sprintf("the organization '%s' has no 'username' or 'org_id' in the manifest or the overlay", org.Name),
+		})
+	}
+	sortIssues(issues)
+	return m, issues, nil
+}
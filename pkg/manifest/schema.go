@@ -0,0 +1,158 @@
+package manifest
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema is the JSON Schema of the manifests.
+//
+//go:embed schema.json
+var Schema []byte
+
+// schema is the subset of JSON Schema used by the schema of the manifests.
+type schema struct {
+	Type                 string             `json:"type"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties *bool              `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	Minimum              *float64           `json:"minimum"`
+	MinLength            *int               `json:"minLength"`
+	Enum                 []string           `json:"enum"`
+}
+
+var manifestSchema = func() *schema {
+	s := &schema{}
+	if err := json.Unmarshal(Schema, s); err != nil {
+		panic(fmt.Sprintf("the schema of the manifests is invalid: %v", err))
+	}
+	return s
+}()
+
+// validate checks the node against the schema, and returns an issue for each violation. The path
+// is the location of the node in the document, etc, 'orgs[0].quotas[1]'.
+func (s *schema) validate(node *yaml.Node, path string) []Issue {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	fail := func(format string, a ...interface{}) []Issue {
+		return []Issue{{
+			Line:    node.Line,
+			Column:  node.Column,
+			Message: fmt.Sprintf("%s: %s", displayPath(path), fmt.Sprintf(format, a...)),
+		}}
+	}
+
+	switch s.Type {
+	case "object":
+		if node.Kind != yaml.MappingNode {
+			return fail("expected an object")
+		}
+		return s.validateObject(node, path)
+	case "array":
+		if node.Kind != yaml.SequenceNode {
+			return fail("expected an array")
+		}
+		var issues []Issue
+		for i, item := range node.Content {
+			if s.Items != nil {
+				issues = append(issues, s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+		return issues
+	case "string":
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!str" {
+			return fail("expected a string")
+		}
+		if s.MinLength != nil && len(node.Value) < *s.MinLength {
+			return fail("must not be empty")
+		}
+		if len(s.Enum) != 0 && !contains(s.Enum, node.Value) {
+			return fail("'%s' is not one of '%s'", node.Value, strings.Join(s.Enum, "', '"))
+		}
+	case "boolean":
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" {
+			return fail("expected a boolean")
+		}
+	case "integer":
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!int" {
+			return fail("expected an integer")
+		}
+		value, err := strconv.ParseInt(node.Value, 0, 64)
+		if err != nil {
+			return fail("expected an integer: %v", err)
+		}
+		if value > math.MaxInt32 {
+			return fail("%d is too large", value)
+		}
+		if s.Minimum != nil && float64(value) < *s.Minimum {
+			return fail("%d is less than %g", value, *s.Minimum)
+		}
+	}
+	return nil
+}
+
+func (s *schema) validateObject(node *yaml.Node, path string) []Issue {
+	var issues []Issue
+	present := make(map[string]bool)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		present[key.Value] = true
+		property, known := s.Properties[key.Value]
+		if !known {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				issues = append(issues, Issue{
+					Line:    key.Line,
+					Column:  key.Column,
+					Message: fmt.Sprintf("%s: the attribute '%s' is unknown", displayPath(path), key.Value),
+				})
+			}
+			continue
+		}
+		issues = append(issues, property.validate(value, joinPath(path, key.Value))...)
+	}
+
+	required := append([]string(nil), s.Required...)
+	sort.Strings(required)
+	for _, name := range required {
+		if !present[name] {
+			issues = append(issues, Issue{
+				Line:    node.Line,
+				Column:  node.Column,
+				Message: fmt.Sprintf("%s: the attribute '%s' is missing", displayPath(path), name),
+			})
+		}
+	}
+	return issues
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "manifest"
+	}
+	return path
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package manifest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Issue is a problem of a manifest, at a position of the file.
+type Issue struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", i.File, i.Line, i.Column, i.Message)
+}
+
+// Position is the position of an element in the manifest file, so that the problems found after
+// parsing it can still be reported at their line.
+type Position struct {
+	Line   int `yaml:"-" json:"-"`
+	Column int `yaml:"-" json:"-"`
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (o *Org) UnmarshalYAML(node *yaml.Node) error {
+	type plain Org
+	if err := node.Decode((*plain)(o)); err != nil {
+		return err
+	}
+	o.Position = Position{node.Line, node.Column}
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (q *Quota) UnmarshalYAML(node *yaml.Node) error {
+	type plain Quota
+	if err := node.Decode((*plain)(q)); err != nil {
+		return err
+	}
+	q.Position = Position{node.Line, node.Column}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "sku_count" {
+			q.hasCount = true
+		}
+	}
+	return nil
+}
+
+// ReadFile reads the manifest file, or the overlay, interpolates the variables, and checks it. The
+// issues are returned together with the manifest, which is nil if the file can't be parsed.
+func ReadFile(file string, vars map[string]string, overlay bool) (*Manifest, []Issue, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("[E] Failed to read the manifest file: %w", err)
+	}
+	data, issues := Interpolate(file, data, vars)
+	if len(issues) != 0 {
+		return nil, issues, nil
+	}
+	if overlay {
+		return ParseOverlay(file, data)
+	}
+	return Parse(file, data)
+}
+
+// Parse parses the manifest, and checks it against the schema and the rules that the schema can't
+// express: the organizations must have a name, a username or an id, and can't have several resource quotas
+// with the same sku and type. JSON is parsed too, as it is a subset of YAML.
+func Parse(file string, data []byte) (*Manifest, []Issue, error) {
+	return parse(file, data, false)
+}
+
+// ParseOverlay parses an overlay, which has the format of a manifest, but may leave out the
+// accounts of the organizations of the base manifest, and the counts of the removed resource quotas.
+func ParseOverlay(file string, data []byte) (*Manifest, []Issue, error) {
+	return parse(file, data, true)
+}
+
+func parse(file string, data []byte, overlay bool) (*Manifest, []Issue, error) {
+	var document yaml.Node
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	err := decoder.Decode(&document)
+	if errors.Is(err, io.EOF) {
+		return nil, []Issue{{File: file, Line: 1, Column: 1, Message: "the manifest is empty"}}, nil
+	}
+	if err != nil {
+		return nil, []Issue{{File: file, Line: 1, Column: 1, Message: err.Error()}}, nil
+	}
+	root := &document
+	if root.Kind == yaml.DocumentNode && len(root.Content) == 1 {
+		root = root.Content[0]
+	}
+
+	issues := manifestSchema.validate(root, "")
+	m := &Manifest{}
+	err = root.Decode(m)
+	switch {
+	case err != nil && len(issues) == 0:
+		return nil, nil, fmt.Errorf("[E] Failed to parse the manifest '%s': %w", file, err)
+	case err != nil:
+		// The schema issues explain why it can't be decoded:
+		m = nil
+	default:
+		issues = append(issues, m.check(overlay)...)
+	}
+	for i := range issues {
+		issues[i].File = file
+	}
+	sortIssues(issues)
+	return m, issues, nil
+}
+
+func sortIssues(issues []Issue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].File != issues[j].File {
+			return issues[i].File < issues[j].File
+		}
+		return issues[i].Line < issues[j].Line
+	})
+}
+
+// check returns the issues that the schema can't express.
+func (m *Manifest) check(overlay bool) []Issue {
+	var issues []Issue
+	issue := func(position Position, format string, a ...interface{}) {
+		issues = append(issues, Issue{
+			Line:    position.Line,
+			Column:  position.Column,
+			Message: fmt.Sprintf(format, a...),
+		})
+	}
+
+	orgs := make(map[string]int)
+	for i, org := range m.Orgs {
+		// The account of a named organization may be given by the overlays, it is checked after merging them:
+		if org.Username == "" && org.OrgID == "" && org.Name == "" {
+			issue(org.Position, "orgs[%d]: the attribute 'username' or 'org_id' is required", i)
+			continue
+		}
+		if first, duplicated := orgs[org.Key()]; duplicated {
+			issue(org.Position, "orgs[%d]: the organization '%s' is already declared at line %d", i, org.Label(), first)
+		}
+		orgs[org.Key()] = org.Line
+
+		seen := make(map[string]int)
+		for j, quota := range org.Quotas {
+			switch {
+			case quota.Remove && !overlay:
+				issue(quota.Position, "orgs[%d].quotas[%d]: the attribute 'remove' is only allowed in the overlays", i, j)
+			case !quota.hasCount && !quota.Remove:
+				issue(quota.Position, "orgs[%d].quotas[%d]: the attribute 'sku_count' is missing", i, j)
+			}
+			key := quota.Sku + "/" + quota.Type
+			if first, duplicated := seen[key]; duplicated {
+				issue(quota.Position, "orgs[%d].quotas[%d]: the sku '%s' of type '%s' is already declared at line %d",
+					i, j, quota.Sku, quota.Type, first)
+				continue
+			}
+			seen[key] = quota.Line
+		}
+	}
+	return issues
+}
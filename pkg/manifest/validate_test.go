@@ -0,0 +1,95 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeManifest(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+	file := filepath.Join(dir, name)
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestReadFileInterpolatesBeforeTheSchemaCheck(t *testing.T) {
+	file := writeManifest(t, t.TempDir(), "quota.yaml", `orgs:
+- username: ${MYQUOTA_TEST_USER}
+  quotas:
+  - sku: MCT3326
+    type: Manual
+    sku_count: ${MYQUOTA_TEST_COUNT}
+`)
+
+	m, issues, err := ReadFile(file, map[string]string{"MYQUOTA_TEST_USER": "sdqe-quota", "MYQUOTA_TEST_COUNT": "3"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("expected no issues, got %v", issues)
+	}
+	if org := m.Orgs[0]; org.Username != "sdqe-quota" || org.Quotas[0].SkuCount != 3 {
+		t.Errorf("expected the variables to be interpolated, got %+v", org)
+	}
+
+	_, issues, err = ReadFile(file, map[string]string{"MYQUOTA_TEST_USER": "sdqe-quota"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "'MYQUOTA_TEST_COUNT' is undefined") || issues[0].Line != 6 {
+		t.Errorf("expected the undefined variable at line 6, got %v", issues)
+	}
+}
+
+func TestBasePathDetectsTheOverlays(t *testing.T) {
+	dir := t.TempDir()
+	base := writeManifest(t, dir, "quota.yaml", "orgs: []\n")
+	tests := []struct {
+		name string
+		base string
+	}{
+		{name: "quota.yaml"},
+		{name: "quota.staging.yaml", base: base},
+		{name: "quota.dev.yaml", base: base},
+		{name: "quota.v2.yaml"},
+		{name: "other.staging.yaml"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file := writeManifest(t, dir, test.name, "orgs: []\n")
+			if got := BasePath(file, "dev"); got != test.base {
+				t.Errorf("expected the base '%s', got '%s'", test.base, got)
+			}
+		})
+	}
+}
+
+func TestReadFileChecksTheOverlays(t *testing.T) {
+	file := writeManifest(t, t.TempDir(), "quota.staging.yaml", `orgs:
+- name: qe
+  quotas:
+  - sku: MCT3326
+    type: Manual
+    remove: true
+`)
+
+	_, issues, err := ReadFile(file, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("expected no issues in the overlay, got %v", issues)
+	}
+
+	_, issues, err = ReadFile(file, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "'remove' is only allowed in the overlays") {
+		t.Errorf("expected 'remove' to be refused in a base manifest, got %v", issues)
+	}
+}
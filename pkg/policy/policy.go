@@ -0,0 +1,108 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AnySku is the key of the maximum counts that applies to the skus that aren't listed.
+const AnySku = "*"
+
+// ErrDenied is wrapped by the errors of the changes that the policy doesn't allow.
+var ErrDenied = errors.New("denied by the policy")
+
+// Policy is the envelope of the changes allowed in a profile. The empty policy allows everything.
+type Policy struct {
+	// MaxCounts are the maximum counts that can be assigned, per sku. The key '*' applies to the
+	// other skus. If it is set and the key '*' isn't, only the listed skus can be assigned.
+	MaxCounts map[string]int `yaml:"max_counts,omitempty"`
+	// AllowedTypes are the types of resource quota that can be assigned or removed.
+	AllowedTypes []string `yaml:"allowed_types,omitempty"`
+	// AllowedOrgs are the ids of the organizations that can be changed. Empty means all.
+	AllowedOrgs []string `yaml:"allowed_orgs,omitempty"`
+	// DeniedOrgs are the ids of the organizations that can't be changed, even if they are allowed.
+	DeniedOrgs []string `yaml:"denied_orgs,omitempty"`
+	// ForbidForce forbids to remove the resource quotas that are in use.
+	ForbidForce bool `yaml:"forbid_force,omitempty"`
+}
+
+// Violation is a change that the policy doesn't allow.
+type Violation struct {
+	Profile string
+	Reason  string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("[E] The policy of the profile '%s' denies the change: %s", v.Profile, v.Reason)
+}
+
+func (v *Violation) Unwrap() error {
+	return ErrDenied
+}
+
+// CheckTarget checks that the resource quota of the type can be changed in the organization.
+func (p *Policy) CheckTarget(profile string, orgID string, qtype string) error {
+	if p == nil {
+		return nil
+	}
+	if contains(p.DeniedOrgs, orgID) {
+		return &Violation{profile, fmt.Sprintf("the organization '%s' is in the denied organizations", orgID)}
+	}
+	if len(p.AllowedOrgs) != 0 && !contains(p.AllowedOrgs, orgID) {
+		return &Violation{profile, fmt.Sprintf("the organization '%s' isn't in the allowed organizations", orgID)}
+	}
+	if len(p.AllowedTypes) != 0 && !contains(p.AllowedTypes, qtype) {
+		return &Violation{profile, fmt.Sprintf("the type '%s' isn't one of the allowed types '%s'",
+			qtype, strings.Join(p.AllowedTypes, "', '"))}
+	}
+	return nil
+}
+
+// CheckAssign checks that the count of the sku can be assigned to the organization.
+func (p *Policy) CheckAssign(profile string, orgID string, sku string, qtype string, count int) error {
+	if p == nil {
+		return nil
+	}
+	if err := p.CheckTarget(profile, orgID, qtype); err != nil {
+		return err
+	}
+	if len(p.MaxCounts) == 0 {
+		return nil
+	}
+	maxCount, limited := p.MaxCounts[sku]
+	if !limited {
+		maxCount, limited = p.MaxCounts[AnySku]
+	}
+	if !limited {
+		return &Violation{profile, fmt.Sprintf("the sku '%s' isn't in the maximum counts", sku)}
+	}
+	if count > maxCount {
+		return &Violation{profile, fmt.Sprintf("the sku '%s' can be assigned at most %d, but %d is requested",
+			sku, maxCount, count)}
+	}
+	return nil
+}
+
+// CheckRemove checks that the resource quota of the sku can be removed from the organization.
+func (p *Policy) CheckRemove(profile string, orgID string, sku string, qtype string, force bool) error {
+	if p == nil {
+		return nil
+	}
+	if err := p.CheckTarget(profile, orgID, qtype); err != nil {
+		return err
+	}
+	if force && p.ForbidForce {
+		return &Violation{profile, fmt.Sprintf("the resource quota of the sku '%s' can't be removed with '--force'", sku)}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
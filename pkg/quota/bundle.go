@@ -0,0 +1,72 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github/yasun1/myquota/pkg/config"
+)
+
+// BundleSkus returns the skus of the bundle with their count and the type, sorted by name. Every
+// sku is checked against the skus of OCM, so that an invalid sku is reported before any change.
+func BundleSkus(ctx context.Context, bundle config.Bundle, qtype string) ([]Sku, error) {
+	skuMap, err := AllSkus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var skus []Sku
+	var invalid []string
+	for _, skuName := range bundle.Skus() {
+		sku, existed := skuMap[skuName]
+		if !existed {
+			invalid = append(invalid, skuName)
+			continue
+		}
+		sku.Allowed = bundle[skuName]
+		sku.Type = qtype
+		skus = append(skus, sku)
+	}
+	if len(invalid) != 0 {
+		return nil, fmt.Errorf("[E] The skus '%s' of the bundle are invalid", strings.Join(invalid, "', '"))
+	}
+	return skus, nil
+}
+
+// FPrintBundleStatus prints whether each resource quota of the bundle is assigned with the count
+// of the bundle, and returns whether all of them are.
+func FPrintBundleStatus(ctx context.Context, orgID string, skus ...Sku) (bool, error) {
+	snapshot, err := TakeSnapshot(ctx, orgID, skus...)
+	if err != nil {
+		return false, err
+	}
+
+	applied := true
+	fmt.Printf("\n>>> The bundle in the organization %s: \n", orgID)
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 8, ' ', 0)
+	fmt.Fprintf(writer, "Name\tType\tWanted\tAssigned\tStatus\t\n")
+	for i, sku := range skus {
+		saved := snapshot.Quotas[i]
+		assigned, status := "-", "missing"
+		if saved.Existed {
+			assigned = fmt.Sprintf("%d", saved.Sku.Allowed)
+			status = "applied"
+			if saved.Sku.Allowed != sku.Allowed {
+				status = "differs"
+			}
+		}
+		if status != "applied" {
+			applied = false
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%d\t%s\t%s\n",
+			sku.Name,
+			sku.Type,
+			sku.Allowed,
+			assigned,
+			status,
+		)
+	}
+	return applied, writer.Flush()
+}
@@ -0,0 +1,228 @@
+package quota
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Status is the result of a threshold check. The values are the exit codes of the Nagios plugins.
+type Status int
+
+const (
+	StatusOK       Status = 0
+	StatusWarning  Status = 1
+	StatusCritical Status = 2
+	StatusUnknown  Status = 3
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusOK:
+		return "OK"
+	case StatusWarning:
+		return "WARNING"
+	case StatusCritical:
+		return "CRITICAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// QuotaCheck is the result of the threshold check of one quota.
+type QuotaCheck struct {
+	Usage
+	Utilization float64
+	Status      Status
+}
+
+// CheckResult is the result of the threshold check of the quotas of an organization.
+type CheckResult struct {
+	OrgID  string
+	Warn   float64
+	Crit   float64
+	Quotas []QuotaCheck
+	// Missing are the quota ids that were requested but have no quota cost in the organization.
+	Missing []string
+	Status  Status
+}
+
+// Utilization returns the percentage of the allowed quota that is consumed. A quota that is
+// consumed without being allowed is reported as fully used.
+func (u Usage) Utilization() float64 {
+	if u.Allowed <= 0 {
+		if u.Consumed > 0 {
+			return 100
+		}
+		return 0
+	}
+	return float64(u.Consumed) * 100 / float64(u.Allowed)
+}
+
+// CheckUsage compares the utilization of the quotas with the warning and critical thresholds,
+// given as percentages. If quotaIDs is empty every allowed or consumed quota is checked,
+// otherwise only the given ones, and the ones without quota cost make the result unknown.
+func CheckUsage(orgID string, usages []Usage, warn float64, crit float64, quotaIDs ...string) *CheckResult {
+	result := &CheckResult{
+		OrgID: orgID,
+		Warn:  warn,
+		Crit:  crit,
+	}
+
+	usageMap := make(map[string]Usage)
+	for _, usage := range usages {
+		usageMap[usage.QuotaID] = usage
+	}
+	if len(quotaIDs) == 0 {
+		for _, usage := range usages {
+			if usage.Allowed > 0 || usage.Consumed > 0 {
+				quotaIDs = append(quotaIDs, usage.QuotaID)
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, quotaID := range quotaIDs {
+		if seen[quotaID] {
+			continue
+		}
+		seen[quotaID] = true
+
+		usage, existed := usageMap[quotaID]
+		if !existed {
+			result.Missing = append(result.Missing, quotaID)
+			continue
+		}
+		check := QuotaCheck{
+			Usage:       usage,
+			Utilization: usage.Utilization(),
+			Status:      StatusOK,
+		}
+		switch {
+		case check.Utilization >= crit:
+			check.Status = StatusCritical
+		case check.Utilization >= warn:
+			check.Status = StatusWarning
+		}
+		result.Quotas = append(result.Quotas, check)
+	}
+
+	result.Status = StatusOK
+	for _, check := range result.Quotas {
+		if check.Status > result.Status {
+			result.Status = check.Status
+		}
+	}
+	if len(result.Missing) != 0 && result.Status < StatusCritical {
+		result.Status = StatusUnknown
+	}
+	return result
+}
+
+// Summary returns the result as a one-line plugin output followed by the performance data, etc:
+//
+//	QUOTA WARNING - 1 warning, 0 critical of 2 quotas: cluster/byoc/osd=85.0% | 'cluster/byoc/osd'=17;16;19;0;20
+//
+// The '|' of the quota ids are replaced with '/', as the plugins separate the performance data with it.
+func (r *CheckResult) Summary() string {
+	summary := r.Text()
+	if perfData := r.PerfData(""); len(perfData) != 0 {
+		summary += " | " + strings.Join(perfData, " ")
+	}
+	return summary
+}
+
+// Text returns the result as a one-line plugin output, without the performance data.
+func (r *CheckResult) Text() string {
+	var warnings, criticals int
+	var details []string
+	for _, check := range r.Quotas {
+		switch check.Status {
+		case StatusWarning:
+			warnings++
+		case StatusCritical:
+			criticals++
+		default:
+			continue
+		}
+		details = append(details, fmt.Sprintf("%s=%.1f%%", pluginLabel(check.QuotaID), check.Utilization))
+	}
+	for _, quotaID := range r.Missing {
+		details = append(details, fmt.Sprintf("%s=missing", pluginLabel(quotaID)))
+	}
+
+	text := fmt.Sprintf("QUOTA %s - %d warning, %d critical of %d quotas in the organization %s",
+		r.Status, warnings, criticals, len(r.Quotas)+len(r.Missing), r.OrgID)
+	if len(details) != 0 {
+		text += ": " + strings.Join(details, ", ")
+	}
+	return text
+}
+
+// PerfData returns the performance data of the quotas, labelled with the quota id after the prefix.
+func (r *CheckResult) PerfData(prefix string) []string {
+	var perfData []string
+	for _, check := range r.Quotas {
+		perfData = append(perfData, fmt.Sprintf("'%s'=%d;%d;%d;0;%d",
+			strings.ReplaceAll(prefix+pluginLabel(check.QuotaID), "'", "''"),
+			check.Consumed,
+			threshold(check.Allowed, r.Warn),
+			threshold(check.Allowed, r.Crit),
+			check.Allowed,
+		))
+	}
+	return perfData
+}
+
+// GroupCheck is the check of an organization of a group, or the error that prevented it.
+type GroupCheck struct {
+	Label  string
+	Result *CheckResult
+	Err    error
+}
+
+// GroupSummary returns the worst status of the organizations of a group, and the plugin output:
+// the counts and the performance data of every organization, labelled with its id, on the first
+// line, followed by the status of each organization, etc:
+//
+//	QUOTA WARNING - 2 organizations in the group ci: 1 OK, 1 warning, 0 critical, 0 unknown | 'org1:cluster/byoc/osd'=17;16;19;0;20 'org2:cluster/byoc/osd'=1;16;19;0;20
+//	QUOTA WARNING - 1 warning, 0 critical of 1 quotas in the organization org1: cluster/byoc/osd=85.0%
+//	QUOTA OK - 0 warning, 0 critical of 1 quotas in the organization org2
+//
+// The organizations that can't be checked are unknown.
+func GroupSummary(name string, checks []GroupCheck) (Status, string) {
+	status := StatusOK
+	counts := make(map[Status]int)
+	var lines, perfData []string
+	for _, check := range checks {
+		orgStatus := StatusUnknown
+		if check.Err != nil {
+			lines = append(lines, fmt.Sprintf("QUOTA %s - %s: %v", orgStatus, check.Label, check.Err))
+		} else {
+			orgStatus = check.Result.Status
+			lines = append(lines, check.Result.Text())
+			perfData = append(perfData, check.Result.PerfData(check.Result.OrgID+":")...)
+		}
+		counts[orgStatus]++
+		if orgStatus == StatusCritical || (orgStatus > status && status != StatusCritical) {
+			status = orgStatus
+		}
+	}
+
+	summary := fmt.Sprintf("QUOTA %s - %d organizations in the group %s: %d OK, %d warning, %d critical, %d unknown",
+		status, len(checks), name,
+		counts[StatusOK], counts[StatusWarning], counts[StatusCritical], counts[StatusUnknown])
+	if len(perfData) != 0 {
+		summary += " | " + strings.Join(perfData, " ")
+	}
+	return status, strings.Join(append([]string{summary}, lines...), "\n")
+}
+
+// pluginLabel returns the quota id without the '|' that separates the performance data.
+func pluginLabel(quotaID string) string {
+	return strings.ReplaceAll(quotaID, "|", "/")
+}
+
+// threshold converts a percentage of the allowed quota to an absolute count.
+func threshold(allowed int, percent float64) int {
+	return int(float64(allowed) * percent / 100)
+}
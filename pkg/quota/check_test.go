@@ -0,0 +1,56 @@
+package quota
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSummarySeparatesThePerformanceDataOnce(t *testing.T) {
+	usages := []Usage{
+		{SkuNames: "MCT3326", QuotaID: "cluster|byoc|osd", Allowed: 20, Consumed: 17},
+		{SkuNames: "MCT4249", QuotaID: "addon|rhoam", Allowed: 10, Consumed: 1},
+	}
+	tests := []struct {
+		name     string
+		quotaIDs []string
+	}{
+		{name: "all quotas"},
+		{name: "missing quota", quotaIDs: []string{"cluster|byoc|osd", "cluster|byoc|rosa"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			summary := CheckUsage("org1", usages, 80, 95, test.quotaIDs...).Summary()
+			if count := strings.Count(summary, "|"); count != 1 {
+				t.Fatalf("expected exactly one '|' in %q, found %d", summary, count)
+			}
+			if !strings.Contains(summary, "'cluster/byoc/osd'=17;16;19;0;20") {
+				t.Errorf("expected the performance data of 'cluster/byoc/osd' in %q", summary)
+			}
+		})
+	}
+}
+
+func TestGroupSummarySeparatesThePerformanceDataOnce(t *testing.T) {
+	usages := []Usage{
+		{SkuNames: "MCT3326", QuotaID: "cluster|byoc|osd", Allowed: 20, Consumed: 17},
+	}
+	checks := []GroupCheck{
+		{Label: "org1", Result: CheckUsage("org1", usages, 80, 95)},
+		{Label: "org2", Result: CheckUsage("org2", usages, 90, 95)},
+		{Label: "org3", Err: errors.New("forbidden")},
+	}
+	status, summary := GroupSummary("ci", checks)
+	if status != StatusUnknown {
+		t.Errorf("expected the status %s, got %s", StatusUnknown, status)
+	}
+	if count := strings.Count(summary, "|"); count != 1 {
+		t.Fatalf("expected exactly one '|' in %q, found %d", summary, count)
+	}
+	first, _, _ := strings.Cut(summary, "\n")
+	for _, perfData := range []string{"'org1:cluster/byoc/osd'=17;16;19;0;20", "'org2:cluster/byoc/osd'=17;18;19;0;20"} {
+		if !strings.Contains(first, perfData) {
+			t.Errorf("expected the performance data %s on the first line %q", perfData, first)
+		}
+	}
+}
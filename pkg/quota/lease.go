@@ -0,0 +1,57 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github/yasun1/myquota/pkg/connection"
+	"github/yasun1/myquota/pkg/lease"
+)
+
+// ErrInUse is returned when reverting a lease would take away resources that are in use.
+var ErrInUse = errors.New("the resource quota is in use")
+
+// RevertLease restores the resource quota to its state before the lease. It doesn't revert the
+// lease, and returns ErrInUse, if the consumption of the quota exceeds what would be allowed after
+// reverting it.
+func RevertLease(ctx context.Context, l *lease.Lease) error {
+	conn, err := connection.SuperAdminConnection()
+	if err != nil {
+		return err
+	}
+	saved := SavedQuota{
+		Sku: Sku{
+			Name:    l.Sku,
+			QuotaID: l.QuotaID,
+			Type:    l.Type,
+			Allowed: l.Previous,
+		},
+		Existed: l.Existed,
+	}
+	resourceQuota, err := findResourceQuota(ctx, conn, l.OrgID, saved.Sku)
+	if err != nil {
+		return err
+	}
+	current := 0
+	if resourceQuota != nil {
+		current = resourceQuota.SkuCount
+	}
+	if !l.Existed {
+		saved.Sku.Allowed = 0
+	}
+
+	// The quota may be allowed by other resource quotas too, so only the difference counts:
+	usage, err := getUsageForQuota(ctx, l.OrgID, saved.Sku)
+	if err != nil {
+		return err
+	}
+	allowedAfter := usage.Allowed - current + saved.Sku.Allowed
+	if usage.Consumed > allowedAfter {
+		slog.DebugContext(ctx, "The resource quota is still in use", "lease", l.ID, "sku", l.Sku,
+			"consumed", usage.Consumed, "allowed_after", allowedAfter, "org_id", l.OrgID)
+		return ErrInUse
+	}
+
+	return RestoreQuota(ctx, l.OrgID, saved)
+}
@@ -0,0 +1,159 @@
+package quota
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github/yasun1/myquota/pkg/term"
+)
+
+// Sort keys of the usages
+const (
+	SortName        = "name"
+	SortAllowed     = "allowed"
+	SortConsumed    = "consumed"
+	SortUtilization = "utilization"
+)
+
+// SortKeys are the valid sort keys.
+var SortKeys = []string{SortName, SortAllowed, SortConsumed, SortUtilization}
+
+// Utilization percentages from which the quota is colored as near full and as full.
+const (
+	nearFullUtilization = 80
+	fullUtilization     = 95
+)
+
+// UsageFilter selects the usages to list. The zero value selects all of them.
+type UsageFilter struct {
+	// OnlyUsed selects the quotas that are consumed.
+	OnlyUsed bool
+	// OnlyAssigned selects the quotas that are allowed.
+	OnlyAssigned bool
+	// Pattern selects the quotas whose id or one of whose skus matches.
+	Pattern *regexp.Regexp
+}
+
+// Match returns whether the usage is selected by the filter.
+func (f UsageFilter) Match(usage Usage) bool {
+	if f.OnlyUsed && usage.Consumed <= 0 {
+		return false
+	}
+	if f.OnlyAssigned && usage.Allowed <= 0 {
+		return false
+	}
+	if f.Pattern == nil || f.Pattern.MatchString(usage.QuotaID) {
+		return true
+	}
+	for _, skuName := range strings.Split(usage.SkuNames, ",") {
+		if skuName != "" && f.Pattern.MatchString(skuName) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterUsages returns the usages selected by the filter.
+func FilterUsages(usages []Usage, filter UsageFilter) []Usage {
+	var selected []Usage
+	for _, usage := range usages {
+		if filter.Match(usage) {
+			selected = append(selected, usage)
+		}
+	}
+	return selected
+}
+
+// ValidSort checks that the key is one of the valid sort keys.
+func ValidSort(key string) error {
+	for _, valid := range SortKeys {
+		if key == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("[E] The sort key '%s' is invalid, valid keys are '%s'", key, strings.Join(SortKeys, "', '"))
+}
+
+// SortUsages sorts the usages by name, or from the highest by allowed, consumed or utilization.
+// The ties are sorted by name, so that the order is stable across the calls.
+func SortUsages(usages []Usage, key string) error {
+	if err := ValidSort(key); err != nil {
+		return err
+	}
+	var value func(usage Usage) float64
+	switch key {
+	case SortName:
+	case SortAllowed:
+		value = func(usage Usage) float64 { return float64(usage.Allowed) }
+	case SortConsumed:
+		value = func(usage Usage) float64 { return float64(usage.Consumed) }
+	case SortUtilization:
+		value = Usage.Utilization
+	}
+	sort.SliceStable(usages, func(i, j int) bool {
+		if value != nil && value(usages[i]) != value(usages[j]) {
+			return value(usages[i]) > value(usages[j])
+		}
+		if usages[i].SkuNames != usages[j].SkuNames {
+			return usages[i].SkuNames < usages[j].SkuNames
+		}
+		return usages[i].QuotaID < usages[j].QuotaID
+	})
+	return nil
+}
+
+// Free returns the quota that is allowed but not consumed.
+func (u Usage) Free() int {
+	if u.Consumed >= u.Allowed {
+		return 0
+	}
+	return u.Allowed - u.Consumed
+}
+
+// UtilizationCell returns the utilization percentage of the usage. With color, the near full
+// quota is yellow and the full quota is red. It must be the last cell of the rows, because the
+// escape sequences would break the alignment of tabwriter.
+func UtilizationCell(usage Usage, color bool) string {
+	utilization := usage.Utilization()
+	cell := fmt.Sprintf("%.1f%%", utilization)
+	switch {
+	case !color:
+	case utilization >= fullUtilization:
+		cell = term.Colorize(term.Red, cell)
+	case utilization >= nearFullUtilization:
+		cell = term.Colorize(term.Yellow, cell)
+	}
+	return cell
+}
+
+// UsageHeader is the header of the tables of the usages, and UsageCells the cells of a usage,
+// shared by the list and the watch of the quota and the list of a group.
+var UsageHeader = []string{"Name", "QuotaID", "Allowed", "Consumed", "Free", "Utilization%"}
+
+func UsageCells(usage Usage, color bool) []string {
+	return []string{
+		usage.SkuNames,
+		usage.QuotaID,
+		strconv.Itoa(usage.Allowed),
+		strconv.Itoa(usage.Consumed),
+		strconv.Itoa(usage.Free()),
+		UtilizationCell(usage, color),
+	}
+}
+
+// FPrintUsages prints the usages with the free quota and the utilization.
+func FPrintUsages(orgID string, usages []Usage, color bool) error {
+	fmt.Printf("\n>>> The quota under the organization %s: \n", orgID)
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 8, ' ', 0)
+	fmt.Fprintf(writer, "%s\t\n", strings.Join(UsageHeader, "\t"))
+	for _, usage := range usages {
+		cells := UsageCells(usage, color)
+		fmt.Fprintf(writer, "%s\n", strings.Join(cells, "\t"))
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,185 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github/yasun1/myquota/pkg/manifest"
+)
+
+// Action is the change of a resource quota needed to reach the state declared in a manifest.
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Change is a change of a resource quota of an organization. Sku has the declared count, and
+// Current the assigned count, which is meaningless when the resource quota is created.
+type Change struct {
+	Org     string
+	OrgID   string
+	Sku     Sku
+	Current int
+	Action  Action
+}
+
+// Plan compares the manifest with the resource quotas of its organizations, and returns the
+// changes to apply, ordered by organization, sku and type. With prune, the resource quotas that
+// aren't declared are deleted, only for the types that the organization declares, so that the
+// quota given by the subscriptions is never touched.
+func Plan(ctx context.Context, m *manifest.Manifest, prune bool) ([]Change, error) {
+	skuMap, err := AllSkus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var invalid []string
+	for _, org := range m.Orgs {
+		for _, declared := range org.Quotas {
+			if _, existed := skuMap[declared.Sku]; !existed {
+				invalid = append(invalid, declared.Sku)
+			}
+		}
+	}
+	if len(invalid) != 0 {
+		return nil, fmt.Errorf("[E] The skus '%s' of the manifest are invalid", strings.Join(invalid, "', '"))
+	}
+
+	var changes []Change
+	for _, org := range m.Orgs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		orgID := org.OrgID
+		if orgID == "" {
+			orgID, err = GetOrgID(ctx, org.Username)
+			if err != nil {
+				return nil, err
+			}
+		}
+		resourceQuotas, err := OrgResourceQuotas(ctx, orgID)
+		if err != nil {
+			return nil, err
+		}
+
+		assigned := make(map[string]int)
+		for _, resourceQuota := range resourceQuotas {
+			assigned[resourceQuota.Sku+"/"+resourceQuota.Type] = resourceQuota.SkuCount
+		}
+		declaredTypes := make(map[string]bool)
+		var orgChanges []Change
+		for _, declared := range org.Quotas {
+			key := declared.Sku + "/" + declared.Type
+			declaredTypes[declared.Type] = true
+			sku := skuMap[declared.Sku]
+			sku.Type = declared.Type
+			sku.Allowed = declared.SkuCount
+			current, existed := assigned[key]
+			delete(assigned, key)
+			change := Change{Org: org.Label(), OrgID: orgID, Sku: sku, Current: current}
+			switch {
+			case !existed:
+				change.Action = ActionCreate
+			case current != declared.SkuCount:
+				change.Action = ActionUpdate
+			default:
+				continue
+			}
+			orgChanges = append(orgChanges, change)
+		}
+		if prune {
+			for _, resourceQuota := range resourceQuotas {
+				if _, undeclared := assigned[resourceQuota.Sku+"/"+resourceQuota.Type]; !undeclared || !declaredTypes[resourceQuota.Type] {
+					continue
+				}
+				sku := skuMap[resourceQuota.Sku]
+				sku.Name = resourceQuota.Sku
+				sku.Type = resourceQuota.Type
+				orgChanges = append(orgChanges, Change{
+					Org:     org.Label(),
+					OrgID:   orgID,
+					Sku:     sku,
+					Current: resourceQuota.SkuCount,
+					Action:  ActionDelete,
+				})
+			}
+		}
+		sort.SliceStable(orgChanges, func(i, j int) bool {
+			if orgChanges[i].Sku.Name != orgChanges[j].Sku.Name {
+				return orgChanges[i].Sku.Name < orgChanges[j].Sku.Name
+			}
+			return orgChanges[i].Sku.Type < orgChanges[j].Sku.Type
+		})
+		changes = append(changes, orgChanges...)
+	}
+	return changes, nil
+}
+
+// ApplyChanges applies the changes one by one, and returns how many of them have been applied.
+// It stops at the first failure, including the cancellation of the context. The deleted resource
+// quotas in use are only deleted with force.
+func ApplyChanges(ctx context.Context, changes []Change, force bool) (int, error) {
+	// Check the policy before changing anything, so that a denied change doesn't leave the others half applied:
+	for _, change := range changes {
+		var err error
+		if change.Action == ActionDelete {
+			err = checkRemovePolicy(change.OrgID, change.Sku, force)
+		} else {
+			err = checkAssignPolicy(change.OrgID, change.Sku)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	for i, change := range changes {
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+		var err error
+		if change.Action == ActionDelete {
+			err = RemoveQuota(ctx, change.OrgID, change.Sku, force)
+		} else {
+			_, err = AssignQuota(ctx, change.OrgID, change.Sku)
+		}
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(changes), nil
+}
+
+// FPrintChanges prints the changes.
+func FPrintChanges(changes []Change) error {
+	if len(changes) == 0 {
+		fmt.Printf("\n>>> No changes, the resource quotas match the manifest.\n")
+		return nil
+	}
+	fmt.Printf("\n>>> The changes to apply: \n")
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 8, ' ', 0)
+	fmt.Fprintf(writer, "Org\tOrgID\tName\tType\tCurrent\tDesired\tAction\t\n")
+	for _, change := range changes {
+		current, desired := fmt.Sprintf("%d", change.Current), fmt.Sprintf("%d", change.Sku.Allowed)
+		switch change.Action {
+		case ActionCreate:
+			current = "-"
+		case ActionDelete:
+			desired = "-"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			change.Org,
+			change.OrgID,
+			change.Sku.Name,
+			change.Sku.Type,
+			current,
+			desired,
+			change.Action,
+		)
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,81 @@
+package quota
+
+import (
+	"fmt"
+	"sort"
+
+	"github/yasun1/myquota/pkg/config"
+	"github/yasun1/myquota/pkg/connection"
+	"github/yasun1/myquota/pkg/policy"
+)
+
+// activePolicy returns the policy that applies to the gateway the commands connect to, and the
+// name of its profile.
+func activePolicy() (*policy.Policy, string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, "", err
+	}
+	gateway, err := connection.GatewayURL()
+	if err != nil {
+		return nil, "", err
+	}
+	return gatewayPolicy(cfg.Profiles, config.ProfileName(), gateway)
+}
+
+// gatewayPolicy returns the policy of the named profile, and refuses it if the profile is for
+// another gateway, etc, with '--profile dev' and 'OCM_ENV=production'. If the profile has no
+// policy, the one of the profile of the gateway applies.
+func gatewayPolicy(profiles map[string]*config.Profile, name string, gateway string) (*policy.Policy, string, error) {
+	if profile := profiles[name]; profile != nil && profile.Policy != nil {
+		if profileURL := connection.ProfileURL(name, profile); profileURL != gateway {
+			return nil, name, &policy.Violation{
+				Profile: name,
+				Reason: fmt.Sprintf("the policy is for the gateway '%s', not for the gateway '%s' the commands connect to",
+					profileURL, gateway),
+			}
+		}
+		return profile.Policy, name, nil
+	}
+
+	names := make([]string, 0, len(profiles))
+	for other := range profiles {
+		names = append(names, other)
+	}
+	sort.Strings(names)
+	for _, other := range names {
+		profile := profiles[other]
+		if profile.Policy != nil && connection.ProfileURL(other, profile) == gateway {
+			return profile.Policy, other, nil
+		}
+	}
+	return nil, name, nil
+}
+
+// checkAssignPolicy checks that the policy of the active profile allows to assign the sku.
+func checkAssignPolicy(orgID string, sku Sku) error {
+	p, profile, err := activePolicy()
+	if err != nil {
+		return err
+	}
+	return p.CheckAssign(profile, orgID, sku.Name, sku.Type, sku.Allowed)
+}
+
+// checkRemovePolicy checks that the policy of the active profile allows to remove the sku.
+func checkRemovePolicy(orgID string, sku Sku, force bool) error {
+	p, profile, err := activePolicy()
+	if err != nil {
+		return err
+	}
+	return p.CheckRemove(profile, orgID, sku.Name, sku.Type, force)
+}
+
+// checkTargetPolicy checks that the policy of the active profile allows to change the resource
+// quota of the type in the organization.
+func checkTargetPolicy(orgID string, sku Sku) error {
+	p, profile, err := activePolicy()
+	if err != nil {
+		return err
+	}
+	return p.CheckTarget(profile, orgID, sku.Type)
+}
@@ -0,0 +1,53 @@
+package quota
+
+import (
+	"errors"
+	"testing"
+
+	"github/yasun1/myquota/pkg/config"
+	"github/yasun1/myquota/pkg/policy"
+)
+
+func TestGatewayPolicyIsBoundToTheGateway(t *testing.T) {
+	const (
+		production = "https://api.openshift.com"
+		staging    = "https://api.stage.openshift.com"
+	)
+	devPolicy := &policy.Policy{MaxCounts: map[string]int{"*": 10}}
+	productionPolicy := &policy.Policy{MaxCounts: map[string]int{"*": 1}}
+	profiles := map[string]*config.Profile{
+		"dev":        {Policy: devPolicy},
+		"local":      {URL: "http://127.0.0.1:8000/"},
+		"production": {Policy: productionPolicy},
+	}
+	tests := []struct {
+		name    string
+		profile string
+		gateway string
+		policy  *policy.Policy
+		denied  bool
+	}{
+		{name: "profile of the gateway", profile: "dev", gateway: staging, policy: devPolicy},
+		{name: "profile of another gateway", profile: "dev", gateway: production, denied: true},
+		{name: "named environment", profile: "production", gateway: production, policy: productionPolicy},
+		{name: "profile without policy", profile: "local", gateway: production, policy: productionPolicy},
+		{name: "gateway without policy", profile: "local", gateway: "http://127.0.0.1:8000"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, _, err := gatewayPolicy(profiles, test.profile, test.gateway)
+			if test.denied {
+				if !errors.Is(err, policy.ErrDenied) {
+					t.Fatalf("expected the policy to be denied, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if p != test.policy {
+				t.Errorf("expected the policy %+v, got %+v", test.policy, p)
+			}
+		})
+	}
+}
@@ -1,31 +1,25 @@
 package quota
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
+	"sync"
 	"text/tabwriter"
 
 	"github/yasun1/myquota/pkg/connection"
 	"github/yasun1/myquota/pkg/constants/http"
 	AMS "github/yasun1/myquota/pkg/endpoints/ams"
-	. "github/yasun1/myquota/pkg/helpers"
-	"github/yasun1/myquota/pkg/logs/debug"
 
 	client "github.com/openshift-online/ocm-sdk-go"
 )
 
 const (
-	skuRBTemplate = `{
-		"sku": "%s",
-		"sku_count": %d,
-		"type": "%s"
-	  }	
-	`
 	manualQuotaType = "Manual"
 )
 
-var SuperAdminConnection = connection.SuperAdminConnection
-
 type Sku struct {
 	Name     string
 	QuotaID  string
@@ -34,105 +28,181 @@ type Sku struct {
 	Consumed int
 }
 
+// checkResponse returns an error if the request failed or if the status of the response is not
+// one of the expected ones. The response is nil when the request never reached the server.
+func checkResponse(resp *client.Response, err error, expectedStatus ...int) error {
+	if err != nil {
+		return err
+	}
+	for _, status := range expectedStatus {
+		if resp.Status() == status {
+			return nil
+		}
+	}
+	return fmt.Errorf("unexpected status %d\n%s", resp.Status(), resp.String())
+}
+
 // var SkuMap = allSkus()
 
-func AllSkus() map[string]Sku {
+func AllSkus(ctx context.Context) (map[string]Sku, error) {
 	params := map[string]interface{}{
 		"size": 10000,
 	}
-	resp, err := AMS.ListSkuRules(SuperAdminConnection, params)
-	if err != nil || resp.Status() != http.HTTPOK {
-		panic(fmt.Errorf("[E] Failed to List skus:\n%v\n%v", err, resp.String()))
+	conn, err := connection.SuperAdminConnection()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := AMS.ListSkuRules(ctx, conn, params)
+	if err = checkResponse(resp, err, http.HTTPOK); err != nil {
+		return nil, fmt.Errorf("[E] Failed to List skus: %w", err)
 	}
 
-	skuMap := make(map[string]Sku)
-	skuRuleItems := DigArray(Parse(resp.Bytes()), "items")
-	for _, skuRule := range skuRuleItems {
-		skuName := DigString(skuRule, "sku")
-		quotaID := DigString(skuRule, "quota_id")
+	skuRules, err := AMS.UnmarshalList[AMS.SkuRule](resp.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("[E] Failed to parse the skus: %w", err)
+	}
 
+	skuMap := make(map[string]Sku)
+	for _, skuRule := range skuRules {
 		sku := Sku{
-			Name:    skuName,
-			QuotaID: quotaID,
+			Name:    skuRule.Sku,
+			QuotaID: skuRule.QuotaID,
 		}
-		skuMap[skuName] = sku
+		skuMap[skuRule.Sku] = sku
 	}
 
 	if len(skuMap) == 0 {
-		panic(fmt.Errorf("[E] No valid skus in OCM\n"))
+		return nil, fmt.Errorf("[E] No valid skus in OCM")
 	}
 
-	if debug.DebugMode() {
-		for _, sku := range skuMap {
-			fmt.Printf("[Debug] %s->%s\n", sku.Name, sku.QuotaID)
-		}
+	for _, sku := range skuMap {
+		slog.DebugContext(ctx, "Found sku", "sku", sku.Name, "quota_id", sku.QuotaID)
 	}
 
-	return skuMap
+	return skuMap, nil
 }
 
-// GetOrgID retturns the ocm organization id of the user
-func GetOrgID(username string) (string, error) {
+// orgIDs caches the organizations of the accounts, they don't change while the tool runs.
+var (
+	orgIDsLock sync.Mutex
+	orgIDs     = make(map[string]string)
+)
+
+// GetOrgID retturns the ocm organization id of the user.
+// The organization of each account is only looked up once.
+func GetOrgID(ctx context.Context, username string) (string, error) {
+	orgIDsLock.Lock()
+	orgID, existed := orgIDs[username]
+	orgIDsLock.Unlock()
+	if existed {
+		return orgID, nil
+	}
+	orgID, err := lookupOrgID(ctx, username)
+	if err != nil {
+		return "", err
+	}
+	orgIDsLock.Lock()
+	orgIDs[username] = orgID
+	orgIDsLock.Unlock()
+	return orgID, nil
+}
+
+func lookupOrgID(ctx context.Context, username string) (string, error) {
 	params := map[string]interface{}{
 		"search": fmt.Sprintf("username is '%s'", username),
 	}
-	resp, err := AMS.ListAccounts(SuperAdminConnection, params)
-	if err != nil || resp.Status() != http.HTTPOK {
-		err = fmt.Errorf("[E] Failed to List account\n%v\n%v\n", err, resp.String())
+	conn, err := connection.SuperAdminConnection()
+	if err != nil {
 		return "", err
 	}
+	resp, err := AMS.ListAccounts(ctx, conn, params)
+	if err = checkResponse(resp, err, http.HTTPOK); err != nil {
+		return "", fmt.Errorf("[E] Failed to List account: %w", err)
+	}
 
-	accountItems := DigArray(Parse(resp.Bytes()), "items")
-	if len(accountItems) != 1 {
-		err = fmt.Errorf("[E] Expect 1 but find %d for the account '%s'\n", len(accountItems), username)
+	accounts, err := AMS.UnmarshalList[AMS.Account](resp.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("[E] Failed to parse the account '%s': %w", username, err)
+	}
+	if len(accounts) != 1 {
+		err = fmt.Errorf("[E] Expect 1 but find %d for the account '%s'", len(accounts), username)
 		return "", err
 	}
 
-	organizationID := DigString(accountItems[0], "organization", "id")
-	if organizationID == "" {
-		err = fmt.Errorf("[E] The orgnization id is empty for the account '%s'\n", username)
+	if accounts[0].Organization == nil || accounts[0].Organization.ID == "" {
+		return "", fmt.Errorf("[E] The orgnization id is empty for the account '%s'", username)
 	}
 
-	return organizationID, err
+	return accounts[0].Organization.ID, nil
 }
 
 // IsAssigned will check whether the quota is assigned
-func IsAssigned(connection *client.Connection, orgID string, sku Sku) (string, bool, error) {
+func IsAssigned(ctx context.Context, connection *client.Connection, orgID string, sku Sku) (string, bool, error) {
+	resourceQuota, err := findResourceQuota(ctx, connection, orgID, sku)
+	if err != nil || resourceQuota == nil {
+		return "", false, err
+	}
+	return resourceQuota.ID, true, nil
+}
+
+// findResourceQuota returns the resource quota of the sku and type in the organization, or nil if
+// it isn't assigned.
+func findResourceQuota(ctx context.Context, connection *client.Connection, orgID string, sku Sku) (*AMS.ResourceQuota, error) {
 	params := map[string]interface{}{
 		"search": fmt.Sprintf("sku is '%s' and type is '%s'", sku.Name, sku.Type),
 	}
-	resp, err := AMS.ListOrgResourceQuotas(connection, orgID, params)
-	if err != nil || resp.Status() != http.HTTPOK {
-		err = fmt.Errorf("[E] Failed to List resource quota\n%v\n%v\n", err, resp.String())
-		return "", false, err
+	resp, err := AMS.ListOrgResourceQuotas(ctx, connection, orgID, params)
+	if err = checkResponse(resp, err, http.HTTPOK); err != nil {
+		return nil, fmt.Errorf("[E] Failed to List resource quota: %w", err)
 	}
 
-	quotaItems := DigArray(Parse(resp.Bytes()), "items")
-	if len(quotaItems) == 0 {
-		return "", false, nil
+	resourceQuotas, err := AMS.UnmarshalList[AMS.ResourceQuota](resp.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("[E] Failed to parse the resource quota: %w", err)
+	}
+	if len(resourceQuotas) == 0 {
+		return nil, nil
 	}
 
-	resourceQuotaID := DigString(quotaItems[0], "id")
-	return resourceQuotaID, true, nil
+	return &resourceQuotas[0], nil
 }
 
-// OrgQuotas get the assigned resource quota in the organization
-func OrgQuotas(orgID string) (map[string]string, error) {
-	skuMap := AllSkus()
-
+// OrgResourceQuotas returns all the resource quotas assigned to the organization.
+func OrgResourceQuotas(ctx context.Context, orgID string) ([]AMS.ResourceQuota, error) {
 	params := map[string]interface{}{
 		"size": 10000,
 	}
-	resp, err := AMS.ListOrgResourceQuotas(SuperAdminConnection, orgID, params)
-	if err != nil || resp.Status() != http.HTTPOK {
-		err = fmt.Errorf("[E] Failed to List resource quota\n")
+	conn, err := connection.SuperAdminConnection()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := AMS.ListOrgResourceQuotas(ctx, conn, orgID, params)
+	if err = checkResponse(resp, err, http.HTTPOK); err != nil {
+		return nil, fmt.Errorf("[E] Failed to List resource quota: %w", err)
+	}
+
+	resourceQuotas, err := AMS.UnmarshalList[AMS.ResourceQuota](resp.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("[E] Failed to parse the resource quota: %w", err)
+	}
+	return resourceQuotas, nil
+}
+
+// OrgQuotas get the assigned resource quota in the organization
+func OrgQuotas(ctx context.Context, orgID string) (map[string]string, error) {
+	skuMap, err := AllSkus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceQuotas, err := OrgResourceQuotas(ctx, orgID)
+	if err != nil {
 		return nil, err
 	}
 
 	quotaMap := make(map[string]string)
-	quotaItems := DigArray(Parse(resp.Bytes()), "items")
-	for _, quota := range quotaItems {
-		skuName := DigString(quota, "sku")
+	for _, quota := range resourceQuotas {
+		skuName := quota.Sku
 		sku := skuMap[skuName]
 
 		if _, existed := quotaMap[sku.QuotaID]; existed {
@@ -144,136 +214,271 @@ func OrgQuotas(orgID string) (map[string]string, error) {
 		quotaMap[sku.QuotaID] = skuName
 	}
 
-	return quotaMap, err
+	return quotaMap, nil
 }
 
 // AssignQuota assigns the quota to the organization.
 // If the resource quota exists, will update its allowed to the new value.
 // If the resource quota does not exist, will create a new resource quota.
-func AssignQuota(orgID string, sku Sku) (string, error) {
-	resourceQuotaID, existed, err := IsAssigned(SuperAdminConnection, orgID, sku)
+// The change must be allowed by the policy of the active profile.
+func AssignQuota(ctx context.Context, orgID string, sku Sku) (string, error) {
+	if err := checkAssignPolicy(orgID, sku); err != nil {
+		return "", err
+	}
+	conn, err := connection.SuperAdminConnection()
+	if err != nil {
+		return "", err
+	}
+	resourceQuotaID, existed, err := IsAssigned(ctx, conn, orgID, sku)
+	if err != nil {
+		return "", err
+	}
+
+	quotaRB, err := json.Marshal(AMS.ResourceQuota{
+		Sku:      sku.Name,
+		SkuCount: sku.Allowed,
+		Type:     sku.Type,
+	})
 	if err != nil {
 		return "", err
 	}
 
 	var resp *client.Response
-	quotaRB := fmt.Sprintf(skuRBTemplate, sku.Name, sku.Allowed, sku.Type)
 	if existed {
-		resp, err = AMS.PatchOrgResourceQuotaByID(SuperAdminConnection, orgID, resourceQuotaID, quotaRB)
+		resp, err = AMS.PatchOrgResourceQuotaByID(ctx, conn, orgID, resourceQuotaID, string(quotaRB))
 	} else {
-		resp, err = AMS.CreateOrgResourceQuota(SuperAdminConnection, orgID, quotaRB)
+		resp, err = AMS.CreateOrgResourceQuota(ctx, conn, orgID, string(quotaRB))
 	}
 
-	if err != nil || (resp.Status() != http.HTTPOK && resp.Status() != http.HTTPCreated) {
-		err = fmt.Errorf("[E] Failed to assign %d %s_%s resource quota to the organization %s:\n%v\n%v",
-			sku.Allowed, sku.Name, sku.Type, orgID, err, resp)
+	if err = checkResponse(resp, err, http.HTTPOK, http.HTTPCreated); err != nil {
+		err = fmt.Errorf("[E] Failed to assign %d %s_%s resource quota to the organization %s: %w",
+			sku.Allowed, sku.Name, sku.Type, orgID, err)
 		return "", err
 	}
 
-	fmt.Printf("Successfully assign %d %s_%s resource quota to the organization %s\n", sku.Allowed, sku.Name, sku.Type, orgID)
-	resourceQuotaID = DigString(Parse(resp.Bytes()), "id")
-	return resourceQuotaID, err
+	slog.InfoContext(ctx, "Successfully assigned the resource quota",
+		"sku", sku.Name, "type", sku.Type, "sku_count", sku.Allowed, "org_id", orgID)
+	resourceQuota, err := AMS.Unmarshal[AMS.ResourceQuota](resp.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("[E] Failed to parse the assigned resource quota: %w", err)
+	}
+	return resourceQuota.ID, nil
 }
 
-// FPrintQuotaCost prints all the resource quotas in the organization.
-func FPrintQuotaCost(orgID string) {
-	quotaMap, err := OrgQuotas(orgID)
+// AssignQuotas assigns the quotas to the organization one by one, and returns how many of them
+// have been applied. It stops at the first failure, including the cancellation of the context.
+func AssignQuotas(ctx context.Context, orgID string, skus ...Sku) (int, error) {
+	// Check the policy before changing anything, so that a denied sku doesn't leave the others half applied:
+	for _, sku := range skus {
+		if err := checkAssignPolicy(orgID, sku); err != nil {
+			return 0, err
+		}
+	}
+	for i, sku := range skus {
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+		if _, err := AssignQuota(ctx, orgID, sku); err != nil {
+			return i, err
+		}
+	}
+	return len(skus), nil
+}
+
+// Usage is the usage of a quota in the organization, with the names of the skus assigned to it.
+type Usage struct {
+	SkuNames string
+	QuotaID  string
+	Allowed  int
+	Consumed int
+}
+
+// OrgUsage returns the usage of all the quotas in the organization.
+func OrgUsage(ctx context.Context, orgID string) ([]Usage, error) {
+	quotaMap, err := OrgQuotas(ctx, orgID)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	params := map[string]interface{}{
 		"size": 10000,
 	}
-	resp, err := AMS.RetrieveQuotaCost(SuperAdminConnection, orgID, params)
-	if err != nil || resp.Status() != http.HTTPOK {
-		err = fmt.Errorf("[E] Failed to get the quota cost of the organization %s\n", orgID)
-		panic(err)
+	conn, err := connection.SuperAdminConnection()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := AMS.RetrieveQuotaCost(ctx, conn, orgID, params)
+	if err = checkResponse(resp, err, http.HTTPOK); err != nil {
+		return nil, fmt.Errorf("[E] Failed to get the quota cost of the organization %s: %w", orgID, err)
+	}
+
+	quotaCosts, err := AMS.UnmarshalList[AMS.QuotaCost](resp.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("[E] Failed to parse the quota cost of the organization %s: %w", orgID, err)
+	}
+
+	var usages []Usage
+	for _, quotaCost := range quotaCosts {
+		usages = append(usages, Usage{
+			SkuNames: quotaMap[quotaCost.QuotaID],
+			QuotaID:  quotaCost.QuotaID,
+			Allowed:  quotaCost.Allowed,
+			Consumed: quotaCost.Consumed,
+		})
+	}
+	return usages, nil
+}
+
+// FPrintQuotaCost prints all the resource quotas in the organization.
+func FPrintQuotaCost(ctx context.Context, orgID string) error {
+	usages, err := OrgUsage(ctx, orgID)
+	if err != nil {
+		return err
 	}
 
 	fmt.Printf("\n>>> The quota under the organization %s: \n", orgID)
-	quotaCostItems := DigArray(Parse(resp.Bytes()), "items")
 	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 8, ' ', 0)
 	fmt.Fprintf(writer, "Name\tQuotaID\tAllowed\tConsumed\t\n")
-	for _, quotaCost := range quotaCostItems {
-		quotaID := DigString(quotaCost, "quota_id")
-		allowed := DigInt(quotaCost, "allowed")
-		consumed := DigInt(quotaCost, "consumed")
-		skuNames := quotaMap[quotaID]
-
+	for _, usage := range usages {
 		fmt.Fprintf(writer, "%s\t%s\t%d\t%d\n",
-			skuNames,
-			quotaID,
-			allowed,
-			consumed,
+			usage.SkuNames,
+			usage.QuotaID,
+			usage.Allowed,
+			usage.Consumed,
 		)
 	}
-	writer.Flush()
+	return writer.Flush()
 }
 
 // getUsageForQuota gets the usage of the specified quota.
-func getUsageForQuota(orgID string, sku Sku) Sku {
+func getUsageForQuota(ctx context.Context, orgID string, sku Sku) (Sku, error) {
 	params := map[string]interface{}{
 		"search": fmt.Sprintf("quota_id is '%s'", sku.QuotaID),
 	}
 
-	resp, err := AMS.RetrieveQuotaCost(SuperAdminConnection, orgID, params)
-	if err != nil || resp.Status() != http.HTTPOK {
-		err = fmt.Errorf("[E] Failed to get the quota cost of the organization %s\n", orgID)
-		panic(err)
+	conn, err := connection.SuperAdminConnection()
+	if err != nil {
+		return sku, err
+	}
+	resp, err := AMS.RetrieveQuotaCost(ctx, conn, orgID, params)
+	if err = checkResponse(resp, err, http.HTTPOK); err != nil {
+		return sku, fmt.Errorf("[E] Failed to get the quota cost of the organization %s: %w", orgID, err)
 	}
 
-	quotaCostItems := DigArray(Parse(resp.Bytes()), "items")
-	if len(quotaCostItems) == 1 {
-		sku.Allowed = DigInt(quotaCostItems[0], "allowed")
-		sku.Consumed = DigInt(quotaCostItems[0], "consumed")
+	quotaCosts, err := AMS.UnmarshalList[AMS.QuotaCost](resp.Bytes())
+	if err != nil {
+		return sku, fmt.Errorf("[E] Failed to parse the quota cost of the organization %s: %w", orgID, err)
+	}
+	if len(quotaCosts) == 1 {
+		sku.Allowed = quotaCosts[0].Allowed
+		sku.Consumed = quotaCosts[0].Consumed
 	}
 
-	return sku
+	return sku, nil
+}
+
+// SkuUsage returns the usage of the specified resource quotas, one per sku.
+func SkuUsage(ctx context.Context, orgID string, skus ...Sku) ([]Usage, error) {
+	var usages []Usage
+	for _, sku := range skus {
+		sku, err := getUsageForQuota(ctx, orgID, sku)
+		if err != nil {
+			return usages, err
+		}
+		usages = append(usages, Usage{
+			SkuNames: sku.Name,
+			QuotaID:  sku.QuotaID,
+			Allowed:  sku.Allowed,
+			Consumed: sku.Consumed,
+		})
+	}
+	return usages, nil
 }
 
 // FPrintUsageForSkus prints the usage of the specified resource quotas.
-func FPrintUsageForSkus(orgID string, skus ...Sku) {
+func FPrintUsageForSkus(ctx context.Context, orgID string, skus ...Sku) error {
+	usages, err := SkuUsage(ctx, orgID, skus...)
+
 	fmt.Printf("\n>>> The quota under the organization %s: \n", orgID)
 	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 8, ' ', 0)
 	fmt.Fprintf(writer, "Name\tQuotaID\tAllowed\tConsumed\t\n")
-	for _, sku := range skus {
-		sku = getUsageForQuota(orgID, sku)
+	for _, usage := range usages {
 		fmt.Fprintf(writer, "%s\t%s\t%d\t%d\n",
-			sku.Name,
-			sku.QuotaID,
-			sku.Allowed,
-			sku.Consumed,
+			usage.SkuNames,
+			usage.QuotaID,
+			usage.Allowed,
+			usage.Consumed,
 		)
 	}
-	writer.Flush()
+	if flushErr := writer.Flush(); err == nil {
+		err = flushErr
+	}
+	return err
 }
 
 // RemoveQuota removes the resource quota from the organization.
-// If the resource quota is in used, option '--force' is required.
-func RemoveQuota(orgID string, sku Sku, force bool) {
-	resourceQuotaID, existed, err := IsAssigned(SuperAdminConnection, orgID, sku)
+// If the resource quota is in used, option '--force' is required, otherwise ErrInUse is returned.
+// The change must be allowed by the policy of the active profile.
+func RemoveQuota(ctx context.Context, orgID string, sku Sku, force bool) error {
+	if err := checkRemovePolicy(orgID, sku, force); err != nil {
+		return err
+	}
+	conn, err := connection.SuperAdminConnection()
+	if err != nil {
+		return err
+	}
+	resourceQuotaID, existed, err := IsAssigned(ctx, conn, orgID, sku)
 	if err != nil {
-		panic(err)
+		return err
 	}
 	if !existed {
-		fmt.Printf("[W] The resource quota with the sku '%s_%s' is not assigned. Give up removing.\n", sku.Name, sku.Type)
-		os.Exit(0)
+		slog.WarnContext(ctx, "The resource quota is not assigned. Give up removing.",
+			"sku", sku.Name, "type", sku.Type, "org_id", orgID)
+		return nil
 	}
 
-	sku = getUsageForQuota(orgID, sku)
+	// The usage of a sku that OCM doesn't know anymore can't be checked, so it counts as in use:
+	if sku.QuotaID == "" && !force {
+		return fmt.Errorf("[W] The sku '%s' is unknown, so the usage of its resource quota can't be checked. "+
+			"If you truly remove the quota, please use with the option '--force': %w", sku.Name, ErrInUse)
+	}
+	if sku.QuotaID != "" {
+		sku, err = getUsageForQuota(ctx, orgID, sku)
+		if err != nil {
+			return err
+		}
+	}
 	if sku.Consumed != 0 && !force {
-		FPrintUsageForSkus(orgID, sku)
-		err = fmt.Errorf("[W] The resource quota is in used. If you truly remove the quota, please use with the option '--force'\n")
-		panic(err)
+		return fmt.Errorf("[W] The resource quota is in used. If you truly remove the quota, please use with the option '--force': %w",
+			ErrInUse)
 	}
 
-	resp, err := AMS.DeleteOrgResourceQuotaByID(SuperAdminConnection, orgID, resourceQuotaID)
-	if err != nil || resp.Status() != http.HTTPNoContent {
-		err = fmt.Errorf("[E] Failed to remove the %s_%s resource quota(%s) from the organization %s:\n%v\n%v\n",
-			sku.Name, sku.Type, resourceQuotaID, orgID, err, resp)
-		panic(err)
+	resp, err := AMS.DeleteOrgResourceQuotaByID(ctx, conn, orgID, resourceQuotaID)
+	if err = checkResponse(resp, err, http.HTTPNoContent); err != nil {
+		return fmt.Errorf("[E] Failed to remove the %s_%s resource quota(%s) from the organization %s: %w",
+			sku.Name, sku.Type, resourceQuotaID, orgID, err)
 	}
 
-	fmt.Printf("Successfully remove the %s_%s resource quota from the organization %s\n", sku.Name, sku.Type, orgID)
+	slog.InfoContext(ctx, "Successfully removed the resource quota",
+		"sku", sku.Name, "type", sku.Type, "org_id", orgID)
+	return nil
+}
+
+// RemoveQuotas removes the quotas from the organization one by one, and returns how many of them
+// have been processed. It stops at the first failure, including the cancellation of the context.
+func RemoveQuotas(ctx context.Context, orgID string, force bool, skus ...Sku) (int, error) {
+	for _, sku := range skus {
+		if err := checkRemovePolicy(orgID, sku, force); err != nil {
+			return 0, err
+		}
+	}
+	for i, sku := range skus {
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+		if err := RemoveQuota(ctx, orgID, sku, force); err != nil {
+			return i, err
+		}
+	}
+	return len(skus), nil
 }
@@ -0,0 +1,80 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+// SkuHolding is a resource quota of a sku held by an organization, with the usage of the quota
+// that the sku is counted in.
+type SkuHolding struct {
+	Org      string
+	OrgID    string
+	Type     string
+	SkuCount int
+	Allowed  int
+	Consumed int
+}
+
+// SkuHoldings returns the resource quotas of the sku in the organization, one per type, or none
+// if the organization doesn't hold the sku.
+func SkuHoldings(ctx context.Context, orgID string, sku Sku) ([]SkuHolding, error) {
+	resourceQuotas, err := OrgResourceQuotas(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	var holdings []SkuHolding
+	for _, resourceQuota := range resourceQuotas {
+		if resourceQuota.Sku != sku.Name {
+			continue
+		}
+		holdings = append(holdings, SkuHolding{
+			OrgID:    orgID,
+			Type:     resourceQuota.Type,
+			SkuCount: resourceQuota.SkuCount,
+		})
+	}
+	if len(holdings) == 0 {
+		return nil, nil
+	}
+
+	usage, err := getUsageForQuota(ctx, orgID, sku)
+	if err != nil {
+		return nil, err
+	}
+	for i := range holdings {
+		holdings[i].Allowed = usage.Allowed
+		holdings[i].Consumed = usage.Consumed
+	}
+	return holdings, nil
+}
+
+// FPrintSkuHoldings prints the organizations that hold the sku, followed by the totals over the
+// queried organizations. The usage of the quota is counted once per organization, even if it
+// holds several types of the sku.
+func FPrintSkuHoldings(sku Sku, holdings []SkuHolding, queried int) error {
+	fmt.Printf("\n>>> The organizations holding the sku %s (%s): \n", sku.Name, sku.QuotaID)
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 8, ' ', 0)
+	fmt.Fprintf(writer, "Org\tType\tSkuCount\tAllowed\tConsumed\t\n")
+	var skuCount, allowed, consumed int
+	orgs := make(map[string]bool)
+	for _, holding := range holdings {
+		fmt.Fprintf(writer, "%s\t%s\t%d\t%d\t%d\n",
+			holding.Org,
+			holding.Type,
+			holding.SkuCount,
+			holding.Allowed,
+			holding.Consumed,
+		)
+		skuCount += holding.SkuCount
+		if !orgs[holding.OrgID] {
+			orgs[holding.OrgID] = true
+			allowed += holding.Allowed
+			consumed += holding.Consumed
+		}
+	}
+	fmt.Fprintf(writer, "Total (%d of %d orgs)\t\t%d\t%d\t%d\n", len(orgs), queried, skuCount, allowed, consumed)
+	return writer.Flush()
+}
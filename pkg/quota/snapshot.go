@@ -0,0 +1,128 @@
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github/yasun1/myquota/pkg/connection"
+	"github/yasun1/myquota/pkg/constants/http"
+	AMS "github/yasun1/myquota/pkg/endpoints/ams"
+)
+
+// Snapshot is the state of some resource quotas of an organization, so that they can be restored
+// after they are changed.
+type Snapshot struct {
+	OrgID  string
+	Quotas []SavedQuota
+}
+
+// SavedQuota is the state of the resource quota of a sku and type. If the resource quota wasn't
+// assigned, Existed is false and restoring it removes it.
+type SavedQuota struct {
+	Sku     Sku
+	Existed bool
+}
+
+// TakeSnapshot saves the state of the resource quotas of the skus in the organization. The type
+// of each sku must be set, the allowed count is ignored.
+func TakeSnapshot(ctx context.Context, orgID string, skus ...Sku) (*Snapshot, error) {
+	conn, err := connection.SuperAdminConnection()
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &Snapshot{OrgID: orgID}
+	for _, sku := range skus {
+		resourceQuota, err := findResourceQuota(ctx, conn, orgID, sku)
+		if err != nil {
+			return nil, err
+		}
+		saved := SavedQuota{Sku: sku}
+		saved.Sku.Allowed = 0
+		if resourceQuota != nil {
+			saved.Existed = true
+			saved.Sku.Allowed = resourceQuota.SkuCount
+		}
+		slog.DebugContext(ctx, "Saved the resource quota", "sku", sku.Name, "type", sku.Type,
+			"assigned", saved.Existed, "sku_count", saved.Sku.Allowed, "org_id", orgID)
+		snapshot.Quotas = append(snapshot.Quotas, saved)
+	}
+	return snapshot, nil
+}
+
+// Restore sets the resource quotas back to their saved state: the ones that existed are assigned
+// their saved count, and the others are removed even if they are in use. It restores as many
+// resource quotas as possible, and returns the errors of the others.
+func (s *Snapshot) Restore(ctx context.Context) error {
+	var errs []error
+	for _, saved := range s.Quotas {
+		errs = append(errs, RestoreQuota(ctx, s.OrgID, saved))
+	}
+	return errors.Join(errs...)
+}
+
+// RestoreQuota sets the resource quota back to its saved state. The resource quota is updated or
+// removed in place, and created again only if it existed and has been removed since. In all the
+// cases, the policy of the active profile must allow to change the organization and the type, but
+// not the count, because the saved state was in place before.
+func RestoreQuota(ctx context.Context, orgID string, saved SavedQuota) error {
+	if err := checkTargetPolicy(orgID, saved.Sku); err != nil {
+		return err
+	}
+	conn, err := connection.SuperAdminConnection()
+	if err != nil {
+		return err
+	}
+	sku := saved.Sku
+	resourceQuota, err := findResourceQuota(ctx, conn, orgID, sku)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case saved.Existed && resourceQuota == nil:
+		// Created directly rather than by AssignQuota, that would check the count:
+		body, err := json.Marshal(AMS.ResourceQuota{
+			Sku:      sku.Name,
+			SkuCount: sku.Allowed,
+			Type:     sku.Type,
+		})
+		if err != nil {
+			return err
+		}
+		resp, err := AMS.CreateOrgResourceQuota(ctx, conn, orgID, string(body))
+		if err = checkResponse(resp, err, http.HTTPOK, http.HTTPCreated); err != nil {
+			return fmt.Errorf("[E] Failed to restore %d %s_%s resource quota in the organization %s: %w",
+				sku.Allowed, sku.Name, sku.Type, orgID, err)
+		}
+		slog.InfoContext(ctx, "Successfully restored the resource quota",
+			"sku", sku.Name, "type", sku.Type, "sku_count", sku.Allowed, "org_id", orgID)
+	case saved.Existed:
+		body, err := json.Marshal(AMS.ResourceQuota{
+			Sku:      sku.Name,
+			SkuCount: sku.Allowed,
+			Type:     sku.Type,
+		})
+		if err != nil {
+			return err
+		}
+		resp, err := AMS.PatchOrgResourceQuotaByID(ctx, conn, orgID, resourceQuota.ID, string(body))
+		if err = checkResponse(resp, err, http.HTTPOK); err != nil {
+			return fmt.Errorf("[E] Failed to restore %d %s_%s resource quota(%s) in the organization %s: %w",
+				sku.Allowed, sku.Name, sku.Type, resourceQuota.ID, orgID, err)
+		}
+		slog.InfoContext(ctx, "Successfully restored the resource quota",
+			"sku", sku.Name, "type", sku.Type, "sku_count", sku.Allowed, "org_id", orgID)
+	case resourceQuota != nil:
+		resp, err := AMS.DeleteOrgResourceQuotaByID(ctx, conn, orgID, resourceQuota.ID)
+		if err = checkResponse(resp, err, http.HTTPNoContent); err != nil {
+			return fmt.Errorf("[E] Failed to remove the %s_%s resource quota(%s) from the organization %s: %w",
+				sku.Name, sku.Type, resourceQuota.ID, orgID, err)
+		}
+		slog.InfoContext(ctx, "Successfully removed the resource quota",
+			"sku", sku.Name, "type", sku.Type, "org_id", orgID)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testToken is an unsigned access token that expires in 2029.
+const testToken = "eyJhbGciOiAibm9uZSIsICJ0eXAiOiAiSldUIn0.eyJ0eXAiOiAiQmVhcmVyIiwgImV4cCI6IDE4OTI0MTM0OTYsICJpYXQiOiAxNzkyNDEzNDk2fQ.sig"
+
+func TestRestoreQuotaRecreatesWithoutCheckingTheCount(t *testing.T) {
+	type resourceQuota struct {
+		Sku      string `json:"sku"`
+		SkuCount int    `json:"sku_count"`
+		Type     string `json:"type"`
+	}
+	var created []resourceQuota
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/api/accounts_mgmt/v1/organizations/org1/resource_quota" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{"kind": "ResourceQuotaList", "items": []}`)
+		case http.MethodPost:
+			var body resourceQuota
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			created = append(created, body)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"id": "rq1", "sku": %q, "sku_count": %d, "type": %q}`, body.Sku, body.SkuCount, body.Type)
+		default:
+			http.Error(w, "unexpected method", http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	// The policy of the profile of the server only allows to assign one of every sku:
+	configDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(configDir, "myquota"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	config := fmt.Sprintf("profiles:\n  staging:\n    url: %s\n    policy:\n      max_counts:\n        '*': 1\n", server.URL)
+	if err := os.WriteFile(filepath.Join(configDir, "myquota", "config.yaml"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	t.Setenv("OCM_ENV", "")
+	t.Setenv("OCM_CLIENT_ID", "")
+	t.Setenv("OCM_CLIENT_SECRET", "")
+	t.Setenv("SUPER_ADMIN_USER_TOKEN", testToken)
+
+	saved := SavedQuota{
+		Sku:     Sku{Name: "MCT3326", QuotaID: "cluster|byoc|osd", Type: "Manual", Allowed: 5},
+		Existed: true,
+	}
+	if err := RestoreQuota(context.Background(), "org1", saved); err != nil {
+		t.Fatalf("expected the resource quota to be recreated, got %v", err)
+	}
+	if len(created) != 1 || created[0].Sku != "MCT3326" || created[0].SkuCount != 5 || created[0].Type != "Manual" {
+		t.Errorf("expected 5 MCT3326_Manual resource quota to be created, got %+v", created)
+	}
+}
@@ -0,0 +1,154 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github/yasun1/myquota/pkg/term"
+)
+
+// UsageFunc returns the current usage of the watched quotas.
+type UsageFunc func(ctx context.Context) ([]Usage, error)
+
+// Watch polls the usage every interval until the context is cancelled. If redraw is true the
+// table is redrawn in place, with the columns of the list and the utilization in color if color
+// is true, and the cells that changed since the previous poll are highlighted. Otherwise only the
+// rows that changed are written, as timestamped events. A failed poll is logged, and retried in
+// the next interval.
+func Watch(ctx context.Context, writer io.Writer, orgID string, interval time.Duration, redraw bool, color bool, usage UsageFunc) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var previous map[string]Usage
+	for {
+		usages, err := usage(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			slog.WarnContext(ctx, "Failed to poll the quota cost", "org_id", orgID, "error", err)
+		default:
+			now := time.Now()
+			if redraw {
+				err = drawTable(writer, orgID, now, interval, usages, previous, color)
+			} else {
+				err = writeChanges(writer, now, usages, previous)
+			}
+			if err != nil {
+				return err
+			}
+			previous = make(map[string]Usage)
+			for _, u := range usages {
+				previous[usageKey(u)] = u
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func usageKey(usage Usage) string {
+	return usage.QuotaID + "\x00" + usage.SkuNames
+}
+
+// drawTable clears the terminal and draws the table. The columns are aligned by hand, because
+// the escape sequences of the highlighted cells would break the alignment of tabwriter.
+func drawTable(writer io.Writer, orgID string, now time.Time, interval time.Duration, usages []Usage, previous map[string]Usage, color bool) error {
+	rows := [][]string{UsageHeader}
+	changes := [][]bool{make([]bool, len(UsageHeader))}
+	for _, usage := range usages {
+		cells := UsageCells(usage, false)
+		rows = append(rows, cells)
+		changed := make([]bool, len(cells))
+		if previous != nil {
+			old, existed := previous[usageKey(usage)]
+			oldCells := UsageCells(old, false)
+			for i := range cells {
+				changed[i] = !existed || cells[i] != oldCells[i]
+			}
+		}
+		changes = append(changes, changed)
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+
+	var builder strings.Builder
+	builder.WriteString(term.ClearScreen())
+	fmt.Fprintf(&builder, "Every %s: the quota under the organization %s at %s\n\n",
+		interval, orgID, now.Format(time.RFC3339))
+	for i, row := range rows {
+		for j, cell := range row {
+			if j < len(row)-1 {
+				cell = fmt.Sprintf("%-*s", widths[j], cell)
+			} else if i > 0 {
+				// The utilization is the last cell, its color doesn't break the alignment:
+				cell = UtilizationCell(usages[i-1], color)
+			}
+			if changes[i][j] {
+				cell = term.Highlight(cell)
+			}
+			builder.WriteString(cell)
+			if j < len(row)-1 {
+				builder.WriteString(strings.Repeat(" ", 8))
+			}
+		}
+		builder.WriteString("\n")
+	}
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
+
+// writeChanges writes one event per row that was added, changed or removed since the previous poll.
+// The first poll writes every row.
+func writeChanges(writer io.Writer, now time.Time, usages []Usage, previous map[string]Usage) error {
+	timestamp := now.Format(time.RFC3339)
+	current := make(map[string]bool)
+	for _, usage := range usages {
+		current[usageKey(usage)] = true
+		event := fmt.Sprintf("time=%s %s", timestamp, usageFields(usage))
+		old, existed := previous[usageKey(usage)]
+		switch {
+		case previous == nil:
+		case !existed:
+			event += " change=added"
+		case old.Allowed != usage.Allowed || old.Consumed != usage.Consumed:
+			event += fmt.Sprintf(" change=updated previous_allowed=%d previous_consumed=%d", old.Allowed, old.Consumed)
+		default:
+			continue
+		}
+		if _, err := fmt.Fprintln(writer, event); err != nil {
+			return err
+		}
+	}
+	for key, old := range previous {
+		if current[key] {
+			continue
+		}
+		_, err := fmt.Fprintf(writer, "time=%s %s change=removed\n", timestamp, usageFields(old))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// usageFields returns the columns of the list of the usage as logfmt fields.
+func usageFields(usage Usage) string {
+	return fmt.Sprintf("name=%q quota_id=%q allowed=%d consumed=%d free=%d utilization=%.1f",
+		usage.SkuNames, usage.QuotaID, usage.Allowed, usage.Consumed, usage.Free(), usage.Utilization())
+}
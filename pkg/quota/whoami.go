@@ -0,0 +1,126 @@
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github/yasun1/myquota/pkg/constants/http"
+	AMS "github/yasun1/myquota/pkg/endpoints/ams"
+
+	client "github.com/openshift-online/ocm-sdk-go"
+)
+
+// Identity describes the account that a connection is acting as.
+type Identity struct {
+	Username string
+	Email    string
+	OrgID    string
+	OrgName  string
+	Roles    []string
+	URL      string
+
+	// QuotaAdmin tells whether the account is allowed to manage the resource quotas. It is only
+	// meaningful when QuotaAdminErr is nil.
+	QuotaAdmin    bool
+	QuotaAdminErr error
+}
+
+// WhoAmI returns the identity of the account that the connection is acting as.
+func WhoAmI(ctx context.Context, conn *client.Connection) (*Identity, error) {
+	resp, err := AMS.RetrieveCurrentAccount(ctx, conn)
+	if err = checkResponse(resp, err, http.HTTPOK); err != nil {
+		return nil, fmt.Errorf("[E] Failed to get the current account: %w", err)
+	}
+	account, err := AMS.Unmarshal[AMS.Account](resp.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("[E] Failed to parse the current account: %w", err)
+	}
+
+	identity := &Identity{
+		Username: account.Username,
+		Email:    account.Email,
+		URL:      conn.URL(),
+	}
+	if account.Organization != nil {
+		identity.OrgID = account.Organization.ID
+		identity.OrgName = account.Organization.Name
+	}
+
+	identity.Roles, err = accountRoles(ctx, conn, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	identity.QuotaAdmin, identity.QuotaAdminErr = canManageQuota(ctx, conn)
+	return identity, nil
+}
+
+// accountRoles returns the roles bound to the account.
+func accountRoles(ctx context.Context, conn *client.Connection, accountID string) ([]string, error) {
+	params := map[string]interface{}{
+		"search": fmt.Sprintf("account.id = '%s'", accountID),
+		"size":   100,
+	}
+	resp, err := AMS.ListRoleBindings(ctx, conn, params)
+	if err = checkResponse(resp, err, http.HTTPOK); err != nil {
+		return nil, fmt.Errorf("[E] Failed to List role bindings: %w", err)
+	}
+	roleBindings, err := AMS.UnmarshalList[AMS.RoleBinding](resp.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("[E] Failed to parse the role bindings: %w", err)
+	}
+
+	var roles []string
+	for _, roleBinding := range roleBindings {
+		if roleBinding.Role != nil {
+			roles = append(roles, roleBinding.Role.ID)
+		}
+	}
+	return roles, nil
+}
+
+// canManageQuota asks AMS whether the caller is allowed to update the resource quotas.
+func canManageQuota(ctx context.Context, conn *client.Connection) (bool, error) {
+	body, err := json.Marshal(AMS.SelfAccessReview{
+		Action:       "update",
+		ResourceType: "ResourceQuota",
+	})
+	if err != nil {
+		return false, err
+	}
+	resp, err := AMS.CreateSelfAccessReview(ctx, conn, string(body))
+	if err = checkResponse(resp, err, http.HTTPOK, http.HTTPCreated); err != nil {
+		return false, fmt.Errorf("[E] Failed to review the access to the resource quotas: %w", err)
+	}
+	review, err := AMS.Unmarshal[AMS.SelfAccessReview](resp.Bytes())
+	if err != nil {
+		return false, fmt.Errorf("[E] Failed to parse the access review: %w", err)
+	}
+	return review.Allowed, nil
+}
+
+// FPrintIdentity prints the identity of the account.
+func FPrintIdentity(identity *Identity) error {
+	org := identity.OrgID
+	if identity.OrgName != "" {
+		org = fmt.Sprintf("%s (%s)", identity.OrgName, identity.OrgID)
+	}
+	roles := strings.Join(identity.Roles, ",")
+	quotaAdmin := fmt.Sprintf("%t", identity.QuotaAdmin)
+	if identity.QuotaAdminErr != nil {
+		quotaAdmin = "unknown"
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 8, ' ', 0)
+	fmt.Fprintf(writer, "Username:\t%s\n", identity.Username)
+	fmt.Fprintf(writer, "Email:\t%s\n", identity.Email)
+	fmt.Fprintf(writer, "Organization:\t%s\n", org)
+	fmt.Fprintf(writer, "Roles:\t%s\n", roles)
+	fmt.Fprintf(writer, "Gateway:\t%s\n", identity.URL)
+	fmt.Fprintf(writer, "QuotaAdmin:\t%s\n", quotaAdmin)
+	return writer.Flush()
+}
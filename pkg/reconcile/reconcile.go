@@ -0,0 +1,238 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github/yasun1/myquota/pkg/audit"
+	"github/yasun1/myquota/pkg/connection"
+	"github/yasun1/myquota/pkg/manifest"
+	"github/yasun1/myquota/pkg/policy"
+	"github/yasun1/myquota/pkg/quota"
+)
+
+// settle is the time waited after a change of the directory before planning, as the editors
+// write a file in several steps.
+const settle = 2 * time.Second
+
+// extensions are the extensions of the manifest files.
+var extensions = []string{".yaml", ".yml", ".json"}
+
+// Reconciler keeps the resource quotas of the organizations in the state declared by the
+// manifests of a directory. The overlays of the manifests are picked for the active environment.
+type Reconciler struct {
+	dir        string
+	interval   time.Duration
+	reportOnly bool
+	prune      bool
+	vars       map[string]string
+	actor      string
+}
+
+// New creates a reconciler of the manifests in the directory, which plans every interval and on
+// every change of the directory. With reportOnly, the drift is only logged.
+func New(dir string, interval time.Duration, reportOnly bool, prune bool, vars map[string]string) *Reconciler {
+	actor := os.Getenv("USER")
+	if current, err := user.Current(); err == nil {
+		actor = current.Username
+	}
+	return &Reconciler{
+		dir:        dir,
+		interval:   interval,
+		reportOnly: reportOnly,
+		prune:      prune,
+		vars:       vars,
+		actor:      actor,
+	}
+}
+
+// Run reconciles until the context is cancelled. The first reconciliation is done immediately.
+func (r *Reconciler) Run(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("[E] Failed to watch the manifests: %w", err)
+	}
+	defer watcher.Close()
+	if err = watcher.Add(r.dir); err != nil {
+		return fmt.Errorf("[E] Failed to watch the directory '%s': %w", r.dir, err)
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	// The settle timer is only started by the changes:
+	changed := time.NewTimer(settle)
+	changed.Stop()
+	defer changed.Stop()
+
+	r.Reconcile(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.Reconcile(ctx)
+		case <-changed.C:
+			slog.InfoContext(ctx, "The manifests changed")
+			r.Reconcile(ctx)
+			ticker.Reset(r.interval)
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return errors.New("[E] The watcher of the manifests stopped")
+			}
+			if isManifest(event.Name) {
+				slog.DebugContext(ctx, "The manifest changed", "file", event.Name, "op", event.Op.String())
+				changed.Reset(settle)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return errors.New("[E] The watcher of the manifests stopped")
+			}
+			slog.WarnContext(ctx, "Failed to watch the manifests", "dir", r.dir, "error", err)
+		}
+	}
+}
+
+// Reconcile plans every manifest of the directory once, and applies the changes unless the
+// reconciler only reports. A reconciliation doesn't last longer than the interval, so that a
+// slow API doesn't pile them up.
+func (r *Reconciler) Reconcile(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, r.interval)
+	defer cancel()
+
+	files, err := Manifests(r.dir)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list the manifests", "dir", r.dir, "error", err)
+		return
+	}
+	drift := 0
+	for _, file := range files {
+		changes, err := r.plan(ctx, file)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to plan the manifest", "file", file, "error", err)
+			continue
+		}
+		drift += len(changes)
+		for _, change := range changes {
+			r.correct(ctx, file, change)
+		}
+	}
+	slog.InfoContext(ctx, "Reconciled the manifests", "manifests", len(files), "drift", drift,
+		"report_only", r.reportOnly)
+}
+
+func (r *Reconciler) plan(ctx context.Context, file string) ([]quota.Change, error) {
+	environment, err := connection.ProfileEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	m, issues, err := manifest.Load(file, "", environment, r.vars)
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) != 0 {
+		for _, issue := range issues {
+			slog.ErrorContext(ctx, "Found a problem in the manifest", "issue", issue.String())
+		}
+		return nil, fmt.Errorf("[E] Found %d problems in the manifest", len(issues))
+	}
+	return quota.Plan(ctx, m, r.prune)
+}
+
+// correct records the change in the audit log, applies it, and records its outcome. The resource
+// quotas in use are never deleted.
+func (r *Reconciler) correct(ctx context.Context, file string, change quota.Change) {
+	attrs := []interface{}{"file", file, "org_id", change.OrgID, "sku", change.Sku.Name, "type", change.Sku.Type,
+		"action", change.Action, "current", change.Current, "desired", change.Sku.Allowed}
+	if r.reportOnly {
+		slog.WarnContext(ctx, "Found a drift", attrs...)
+		return
+	}
+
+	event := audit.Event{
+		Actor:  r.actor,
+		Source: "reconcile " + file,
+		Action: string(change.Action),
+		OrgID:  change.OrgID,
+		Sku:    change.Sku.Name,
+		Type:   change.Sku.Type,
+	}
+	if change.Action != quota.ActionDelete {
+		count := change.Sku.Allowed
+		event.Count = &count
+	}
+
+	// The change is recorded before it is applied, and isn't applied if it can't be audited:
+	intent := event
+	intent.Outcome = audit.OutcomeIntent
+	if err := audit.Record(intent); err != nil {
+		slog.ErrorContext(ctx, "Skipped the correction of the drift, it can't be audited", append(attrs, "error", err)...)
+		return
+	}
+	_, err := quota.ApplyChanges(ctx, []quota.Change{change}, false)
+	switch {
+	case err == nil:
+		event.Outcome = audit.OutcomeSuccess
+		slog.InfoContext(ctx, "Corrected the drift", attrs...)
+	case errors.Is(err, policy.ErrDenied), errors.Is(err, quota.ErrInUse):
+		event.Outcome = audit.OutcomeDenied
+		event.Error = err.Error()
+		slog.WarnContext(ctx, "Refused to correct the drift", append(attrs, "error", err)...)
+	default:
+		event.Outcome = audit.OutcomeFailure
+		event.Error = err.Error()
+		slog.ErrorContext(ctx, "Failed to correct the drift", append(attrs, "error", err)...)
+	}
+	if err = audit.Record(event); err != nil {
+		slog.ErrorContext(ctx, "Failed to record the outcome of the correction of the drift",
+			append(attrs, "outcome", event.Outcome, "error", err)...)
+	}
+}
+
+// Manifests returns the base manifests of the directory, sorted by name. The overlays, etc,
+// 'quota.staging.yaml' next to 'quota.yaml', are left out, as they are loaded with their base.
+func Manifests(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("[E] Failed to read the directory of the manifests: %w", err)
+	}
+	active, err := connection.ProfileEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || !isManifest(entry.Name()) {
+			continue
+		}
+		file := filepath.Join(dir, entry.Name())
+		if manifest.BasePath(file, active) != "" {
+			continue
+		}
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func isManifest(name string) bool {
+	base := filepath.Base(name)
+	if strings.HasPrefix(base, ".") {
+		return false
+	}
+	for _, ext := range extensions {
+		if filepath.Ext(base) == ext {
+			return true
+		}
+	}
+	return false
+}
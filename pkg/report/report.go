@@ -0,0 +1,175 @@
+package report
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github/yasun1/myquota/pkg/quota"
+)
+
+// Report formats
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// Report is the quota of an organization, with the metadata of its generation.
+type Report struct {
+	Environment string
+	Generated   time.Time
+	Username    string
+	OrgID       string
+	// Warn is the utilization percentage from which a quota is highlighted as near full.
+	Warn   float64
+	Quotas []Quota
+}
+
+// Quota is a row of the report: the skus counted in a quota and its usage.
+type Quota struct {
+	QuotaID     string
+	Skus        []string
+	Allowed     int
+	Consumed    int
+	Free        int
+	Utilization float64
+	NearFull    bool
+}
+
+// New creates the report of the usages of the organization, ordered by quota id. The quotas that
+// are neither allowed nor consumed are left out.
+func New(environment string, username string, orgID string, warn float64, usages []quota.Usage) *Report {
+	r := &Report{
+		Environment: environment,
+		Generated:   time.Now().UTC(),
+		Username:    username,
+		OrgID:       orgID,
+		Warn:        warn,
+	}
+	for _, usage := range usages {
+		if usage.Allowed == 0 && usage.Consumed == 0 {
+			continue
+		}
+		var skus []string
+		if usage.SkuNames != "" {
+			skus = strings.Split(usage.SkuNames, ",")
+			sort.Strings(skus)
+		}
+		free := usage.Allowed - usage.Consumed
+		if free < 0 {
+			free = 0
+		}
+		utilization := usage.Utilization()
+		r.Quotas = append(r.Quotas, Quota{
+			QuotaID:     usage.QuotaID,
+			Skus:        skus,
+			Allowed:     usage.Allowed,
+			Consumed:    usage.Consumed,
+			Free:        free,
+			Utilization: utilization,
+			NearFull:    utilization >= warn,
+		})
+	}
+	sort.Slice(r.Quotas, func(i, j int) bool {
+		return r.Quotas[i].QuotaID < r.Quotas[j].QuotaID
+	})
+	return r
+}
+
+// NearFull returns the number of the quotas that are near full.
+func (r *Report) NearFull() int {
+	count := 0
+	for _, q := range r.Quotas {
+		if q.NearFull {
+			count++
+		}
+	}
+	return count
+}
+
+var funcs = map[string]interface{}{
+	"join": strings.Join,
+	"timestamp": func(t time.Time) string {
+		return t.Format(time.RFC3339)
+	},
+	"percent": func(value float64) string {
+		return fmt.Sprintf("%.1f%%", value)
+	},
+	// cell escapes the characters that break a Markdown table:
+	"cell": func(value string) string {
+		return strings.ReplaceAll(value, "|", "\\|")
+	},
+}
+
+var markdownTemplate = template.Must(template.New("markdown").Funcs(funcs).Parse(
+	`# Quota report of the organization {{ .OrgID }}
+
+| | |
+|---|---|
+| Environment | {{ .Environment }} |
+| Organization | {{ .OrgID }} |
+{{- if .Username }}
+| Account | {{ .Username }} |
+{{- end }}
+| Generated | {{ timestamp .Generated }} |
+| Near full | {{ .NearFull }} of {{ len .Quotas }} quotas at {{ percent .Warn }} or more |
+
+| Quota ID | SKUs | Allowed | Consumed | Free | Utilization |
+|---|---|--:|--:|--:|--:|
+{{- range .Quotas }}
+{{- if .NearFull }}
+| **{{ cell .QuotaID }}** | {{ cell (join .Skus ", ") }} | {{ .Allowed }} | {{ .Consumed }} | {{ .Free }} | **{{ percent .Utilization }}** :warning: |
+{{- else }}
+| {{ cell .QuotaID }} | {{ cell (join .Skus ", ") }} | {{ .Allowed }} | {{ .Consumed }} | {{ .Free }} | {{ percent .Utilization }} |
+{{- end }}
+{{- end }}
+`))
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("html").Funcs(funcs).Parse(
+	`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Quota report of the organization {{ .OrgID }}</title>
+<style>
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+td.number { text-align: right; }
+tr.near-full { background-color: #fde2e1; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>Quota report of the organization {{ .OrgID }}</h1>
+<table>
+<tr><th>Environment</th><td>{{ .Environment }}</td></tr>
+<tr><th>Organization</th><td>{{ .OrgID }}</td></tr>
+{{- if .Username }}
+<tr><th>Account</th><td>{{ .Username }}</td></tr>
+{{- end }}
+<tr><th>Generated</th><td>{{ timestamp .Generated }}</td></tr>
+<tr><th>Near full</th><td>{{ .NearFull }} of {{ len .Quotas }} quotas at {{ percent .Warn }} or more</td></tr>
+</table>
+<table>
+<tr><th>Quota ID</th><th>SKUs</th><th>Allowed</th><th>Consumed</th><th>Free</th><th>Utilization</th></tr>
+{{- range .Quotas }}
+<tr{{ if .NearFull }} class="near-full"{{ end }}><td>{{ .QuotaID }}</td><td>{{ join .Skus ", " }}</td><td class="number">{{ .Allowed }}</td><td class="number">{{ .Consumed }}</td><td class="number">{{ .Free }}</td><td class="number">{{ percent .Utilization }}</td></tr>
+{{- end }}
+</table>
+</body>
+</html>
+`))
+
+// Write writes the report in the given format.
+func (r *Report) Write(writer io.Writer, format string) error {
+	switch format {
+	case FormatMarkdown:
+		return markdownTemplate.Execute(writer, r)
+	case FormatHTML:
+		return htmlTemplate.Execute(writer, r)
+	}
+	return fmt.Errorf("[E] The format '%s' is invalid, valid formats are '%s' and '%s'", format, FormatMarkdown, FormatHTML)
+}
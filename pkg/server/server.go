@@ -0,0 +1,330 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github/yasun1/myquota/pkg/audit"
+	"github/yasun1/myquota/pkg/config"
+	"github/yasun1/myquota/pkg/policy"
+	"github/yasun1/myquota/pkg/quota"
+)
+
+const (
+	apiPrefix   = "/api/v1/orgs/"
+	defaultType = "Manual"
+	maxBodySize = 1 << 20
+)
+
+// Server is the REST API that lets the holders of the API keys read and change the quota of the
+// organizations they are granted, without the credentials of the tool.
+type Server struct {
+	keys map[string]*config.APIKey
+
+	// lock serializes the changes, because assigning checks whether the resource quota exists
+	// before creating it.
+	lock sync.Mutex
+}
+
+// New creates the server that accepts the given API keys, indexed by the name of their holder.
+func New(keys map[string]*config.APIKey) (*Server, error) {
+	for name, key := range keys {
+		if _, err := hex.DecodeString(key.KeySHA256); err != nil || len(key.KeySHA256) != 2*sha256.Size {
+			return nil, fmt.Errorf("[E] The 'key_sha256' of the API key '%s' isn't a SHA-256 digest", name)
+		}
+	}
+	return &Server{keys: keys}, nil
+}
+
+// assignRequest is the body of the requests that assign a resource quota.
+type assignRequest struct {
+	Count *int   `json:"count"`
+	Type  string `json:"type,omitempty"`
+}
+
+// usageResponse is an item of the usage of the quota of an organization.
+type usageResponse struct {
+	Sku      string `json:"sku"`
+	QuotaID  string `json:"quota_id"`
+	Allowed  int    `json:"allowed"`
+	Consumed int    `json:"consumed"`
+}
+
+// apiError is an error with the HTTP status of the response.
+type apiError struct {
+	status  int
+	outcome string
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func denied(status int, format string, a ...interface{}) error {
+	return &apiError{status: status, outcome: audit.OutcomeDenied, message: fmt.Sprintf(format, a...)}
+}
+
+// ServeHTTP implements http.Handler. The routes are:
+//
+//	GET    /api/v1/orgs/{org}/usage
+//	PUT    /api/v1/orgs/{org}/quotas/{sku}          {"count": 5, "type": "Manual"}
+//	DELETE /api/v1/orgs/{org}/quotas/{sku}?type=Manual&force=true
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/healthz" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	event := audit.Event{
+		Source: r.RemoteAddr,
+		Action: fmt.Sprintf("%s %s", r.Method, r.URL.Path),
+	}
+	result, err := s.handle(r, &event)
+	status := http.StatusOK
+	body := result
+	event.Outcome = audit.OutcomeSuccess
+	if err != nil {
+		// The errors of AMS are only logged, as they may reveal more than the caller is granted:
+		apiErr := &apiError{
+			status:  http.StatusInternalServerError,
+			outcome: audit.OutcomeFailure,
+			message: "Failed to process the request, the error is in the log of the server",
+		}
+		if errors.Is(err, policy.ErrDenied) {
+			apiErr = &apiError{status: http.StatusForbidden, outcome: audit.OutcomeDenied, message: err.Error()}
+		} else if !errors.As(err, &apiErr) {
+			slog.ErrorContext(r.Context(), "Failed to process the request", "method", r.Method,
+				"path", r.URL.Path, "actor", event.Actor, "error", err)
+		}
+		event.Outcome = apiErr.outcome
+		event.Error = err.Error()
+		status = apiErr.status
+		body = map[string]string{"error": apiErr.message}
+	}
+
+	// The request fails if it can't be audited:
+	if err = audit.Record(event); err != nil {
+		status = http.StatusInternalServerError
+		body = map[string]string{"error": "Failed to record the audit event of the request"}
+	}
+	slog.InfoContext(r.Context(), "Served the request", "method", r.Method, "path", r.URL.Path,
+		"actor", event.Actor, "status", status, "outcome", event.Outcome, "error", event.Error)
+	writeJSON(w, status, body)
+}
+
+// recordIntent records the change in the audit file before it is applied, and refuses it if the
+// audit event can't be recorded, so that no change is applied without being audited.
+func recordIntent(event audit.Event) error {
+	event.Outcome = audit.OutcomeIntent
+	if err := audit.Record(event); err != nil {
+		return &apiError{
+			status:  http.StatusInternalServerError,
+			outcome: audit.OutcomeFailure,
+			message: "Failed to record the audit event of the request, the change isn't applied",
+		}
+	}
+	return nil
+}
+
+func (s *Server) handle(r *http.Request, event *audit.Event) (interface{}, error) {
+	name, key, err := s.authenticate(r)
+	if err != nil {
+		return nil, err
+	}
+	event.Actor = name
+
+	// Parse the path, etc, '{org}/usage' or '{org}/quotas/{sku}':
+	if !strings.HasPrefix(r.URL.Path, apiPrefix) {
+		return nil, denied(http.StatusNotFound, "The path '%s' doesn't exist", r.URL.Path)
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, apiPrefix), "/")
+	event.OrgID = parts[0]
+	if !contains(key.Orgs, event.OrgID) {
+		return nil, denied(http.StatusForbidden, "The API key '%s' isn't allowed to access the organization '%s'",
+			name, event.OrgID)
+	}
+
+	switch {
+	case len(parts) == 2 && parts[1] == "usage" && r.Method == http.MethodGet:
+		event.Action = "usage"
+		return s.usage(r.Context(), event.OrgID)
+	case len(parts) == 3 && parts[1] == "quotas" && r.Method == http.MethodPut:
+		event.Action = "assign"
+		event.Sku = parts[2]
+		return s.assign(r, name, key, event)
+	case len(parts) == 3 && parts[1] == "quotas" && r.Method == http.MethodDelete:
+		event.Action = "remove"
+		event.Sku = parts[2]
+		return s.remove(r, name, key, event)
+	}
+	return nil, denied(http.StatusNotFound, "The route '%s %s' doesn't exist", r.Method, r.URL.Path)
+}
+
+// authenticate returns the API key of the bearer token of the request.
+func (s *Server) authenticate(r *http.Request) (string, *config.APIKey, error) {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || token == "" {
+		return "", nil, denied(http.StatusUnauthorized, "The API key is required as a bearer token")
+	}
+	digest := sha256.Sum256([]byte(token))
+	for name, key := range s.keys {
+		expected, _ := hex.DecodeString(key.KeySHA256)
+		if subtle.ConstantTimeCompare(digest[:], expected) == 1 {
+			return name, key, nil
+		}
+	}
+	return "", nil, denied(http.StatusUnauthorized, "The API key is invalid")
+}
+
+func (s *Server) usage(ctx context.Context, orgID string) (interface{}, error) {
+	usages, err := quota.OrgUsage(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	items := []usageResponse{}
+	for _, usage := range usages {
+		items = append(items, usageResponse{
+			Sku:      usage.SkuNames,
+			QuotaID:  usage.QuotaID,
+			Allowed:  usage.Allowed,
+			Consumed: usage.Consumed,
+		})
+	}
+	return map[string]interface{}{"items": items}, nil
+}
+
+// lookupSku checks that the key is allowed to change the sku with the type, and returns it.
+func (s *Server) lookupSku(ctx context.Context, name string, key *config.APIKey, skuName string, qtype string) (quota.Sku, error) {
+	if _, allowed := key.Skus[skuName]; !allowed {
+		return quota.Sku{}, denied(http.StatusForbidden, "The API key '%s' isn't allowed to change the sku '%s'",
+			name, skuName)
+	}
+	types := key.Types
+	if len(types) == 0 {
+		types = []string{defaultType}
+	}
+	if !contains(types, qtype) {
+		return quota.Sku{}, denied(http.StatusForbidden, "The API key '%s' isn't allowed to change the type '%s'",
+			name, qtype)
+	}
+	skuMap, err := quota.AllSkus(ctx)
+	if err != nil {
+		return quota.Sku{}, err
+	}
+	sku, existed := skuMap[skuName]
+	if !existed {
+		return quota.Sku{}, denied(http.StatusNotFound, "The sku '%s' is invalid", skuName)
+	}
+	return sku, nil
+}
+
+func (s *Server) assign(r *http.Request, name string, key *config.APIKey, event *audit.Event) (interface{}, error) {
+	var body assignRequest
+	if err := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxBodySize)).Decode(&body); err != nil {
+		return nil, denied(http.StatusBadRequest, "Failed to parse the body: %v", err)
+	}
+	if body.Count == nil {
+		return nil, denied(http.StatusBadRequest, "The attribute 'count' of the body is mandatory")
+	}
+	if body.Type == "" {
+		body.Type = defaultType
+	}
+	event.Count = body.Count
+	event.Type = body.Type
+
+	sku, err := s.lookupSku(r.Context(), name, key, event.Sku, event.Type)
+	if err != nil {
+		return nil, err
+	}
+	if maxCount := key.Skus[event.Sku]; *body.Count < 0 || *body.Count > maxCount {
+		return nil, denied(http.StatusForbidden, "The count of the sku '%s' must be between 0 and %d", event.Sku, maxCount)
+	}
+	sku.Allowed = *body.Count
+	sku.Type = body.Type
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err = recordIntent(*event); err != nil {
+		return nil, err
+	}
+	id, err := quota.AssignQuota(r.Context(), event.OrgID, sku)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"id":        id,
+		"sku":       sku.Name,
+		"type":      sku.Type,
+		"sku_count": sku.Allowed,
+	}, nil
+}
+
+func (s *Server) remove(r *http.Request, name string, key *config.APIKey, event *audit.Event) (interface{}, error) {
+	query := r.URL.Query()
+	event.Type = query.Get("type")
+	if event.Type == "" {
+		event.Type = defaultType
+	}
+	if force := query.Get("force"); force != "" {
+		var err error
+		if event.Force, err = strconv.ParseBool(force); err != nil {
+			return nil, denied(http.StatusBadRequest, "The parameter 'force' must be a boolean")
+		}
+	}
+	if event.Force && !key.AllowForce {
+		return nil, denied(http.StatusForbidden, "The API key '%s' isn't allowed to force the removal", name)
+	}
+
+	sku, err := s.lookupSku(r.Context(), name, key, event.Sku, event.Type)
+	if err != nil {
+		return nil, err
+	}
+	sku.Type = event.Type
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err = recordIntent(*event); err != nil {
+		return nil, err
+	}
+	err = quota.RemoveQuota(r.Context(), event.OrgID, sku, event.Force)
+	if errors.Is(err, quota.ErrInUse) {
+		return nil, &apiError{
+			status:  http.StatusConflict,
+			outcome: audit.OutcomeFailure,
+			message: fmt.Sprintf("The resource quota of the sku '%s' is in use, it can only be removed with 'force=true'", sku.Name),
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"sku":  sku.Name,
+		"type": sku.Type,
+	}, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,29 @@
+package term
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// IsForeground returns whether the file is the controlling terminal of the process, and the
+// process group of the process is its foreground process group, so that the process can hand
+// the terminal over to a command.
+func IsForeground(file *os.File) bool {
+	if !IsTerminal(file) {
+		return false
+	}
+	pgrp, err := unix.IoctlGetInt(int(file.Fd()), unix.TIOCGPGRP)
+	return err == nil && pgrp == unix.Getpgrp()
+}
+
+// TakeForeground makes the process group of the process the foreground process group of the
+// terminal again, after a command ran in the foreground.
+func TakeForeground(file *os.File) error {
+	// A background process group gets SIGTTOU when it changes the foreground process group:
+	signal.Ignore(syscall.SIGTTOU)
+	defer signal.Reset(syscall.SIGTTOU)
+	return unix.IoctlSetPointerInt(int(file.Fd()), unix.TIOCSPGRP, unix.Getpgrp())
+}
@@ -0,0 +1,51 @@
+package term
+
+import (
+	"os"
+)
+
+// ANSI escape sequences
+const (
+	clearScreen = "\033[H\033[2J"
+	reverse     = "\033[7m"
+	reset       = "\033[0m"
+)
+
+// Colors of the text
+const (
+	Red    = "\033[31m"
+	Yellow = "\033[33m"
+)
+
+// IsTerminal returns whether the file is a terminal, so that it can be redrawn and colored.
+func IsTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// UseColor returns whether the output to the file can be colored: it must be a terminal, and the
+// colors must not be disabled by 'NO_COLOR' or by a dumb terminal.
+func UseColor(file *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return IsTerminal(file)
+}
+
+// ClearScreen returns the sequence that clears the terminal and moves the cursor to the top left corner.
+func ClearScreen() string {
+	return clearScreen
+}
+
+// Highlight returns the text in reverse video.
+func Highlight(text string) string {
+	return reverse + text + reset
+}
+
+// Colorize returns the text in the given color.
+func Colorize(color string, text string) string {
+	return color + text + reset
+}
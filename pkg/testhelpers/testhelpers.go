@@ -0,0 +1,106 @@
+/*
+Copyright (c) 2018 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains helper functions for the tests. They wrap the functions of the 'helpers'
+// package and abort the test with a Gomega assertion instead of returning an error. It must
+// only be imported by tests.
+
+package testhelpers
+
+import (
+	// nolint
+	. "github.com/onsi/ginkgo"
+	// nolint
+	. "github.com/onsi/gomega"
+
+	client "github.com/openshift-online/ocm-sdk-go"
+
+	"github/yasun1/myquota/pkg/helpers"
+)
+
+const skipAuth = true
+
+// Parse parses the given JSON data and returns a map of strings containing the result. If the data
+// isn't valid JSON then the test will be aborted with an error.
+func Parse(data []byte) map[string]interface{} {
+	object, err := helpers.Parse(data)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	return object
+}
+
+// DigInt tries to find an attribute inside the given object with the given path, and returns its
+// value, assuming that it is an integer. If there is no attribute with the given path then the test
+// will be aborted with an error.
+func DigInt(object interface{}, keys ...interface{}) int {
+	result, err := helpers.DigInt(object, keys...)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	return result
+}
+
+// DigFloat tries to find an attribute inside the given object with the given path, and returns its
+// value, assuming that it is an floating point number. If there is no attribute with the given path
+// then the test will be aborted with an error.
+func DigFloat(object interface{}, keys ...interface{}) float64 {
+	result, err := helpers.DigFloat(object, keys...)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	return result
+}
+
+// DigObject tries to find an attribute inside the given object with the given path, and returns its
+// value. If there is no attribute with the given path then the test will be aborted with an error.
+func DigObject(object interface{}, keys ...interface{}) interface{} {
+	result, err := helpers.DigObject(object, keys...)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	return result
+}
+
+// DigArray tries to find an array inside the given object with the given path, and returns its
+// value. If there is no attribute with the given path then the test will be aborted with an error.
+func DigArray(object interface{}, keys ...interface{}) []interface{} {
+	result, err := helpers.DigArray(object, keys...)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	return result
+}
+
+// Template processes the given template the same way as helpers.Template, but the test will be
+// aborted if the template can't be processed.
+func Template(source string, args ...interface{}) string {
+	result, err := helpers.Template(source, args...)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	return result
+}
+
+// CheckResponse is used to checking
+func CheckResponse(response *client.Response, err error, expectedStatus int) {
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	ExpectWithOffset(1, response.Status()).To(Equal(expectedStatus))
+}
+
+// ReadResult checks the response and returns its parsed body.
+func ReadResult(response *client.Response, err error, expectedStatus int) map[string]interface{} {
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	ExpectWithOffset(1, response.Status()).To(Equal(expectedStatus))
+	object, err := helpers.Parse(response.Bytes())
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	return object
+}
+
+// CheckSkipAuth skips the test when the authorization can't be checked.
+func CheckSkipAuth() {
+	if skipAuth {
+		Skip("Test skipped due to authorization issue")
+	}
+}
@@ -0,0 +1,34 @@
+package timeout
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+var timeout time.Duration
+
+// AddFlag adds the timeout flag to the given set of command line flags.
+func AddFlag(flags *pflag.FlagSet) {
+	flags.DurationVar(
+		&timeout,
+		"timeout",
+		0,
+		"The maximum time the command is allowed to run, etc, 30s or 5m. Zero means no timeout.",
+	)
+}
+
+// Timeout returns the value of timeout
+func Timeout() time.Duration {
+	return timeout
+}
+
+// WithTimeout derives a context from the parent that is cancelled when the timeout expires.
+// If no timeout is set, the returned context is only cancelled together with the parent.
+func WithTimeout(parent context.Context) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, timeout)
+}